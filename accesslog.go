@@ -0,0 +1,166 @@
+/*
+ * Copyright 2016 Fabrício Godoy
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package web
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	// AccessLogCommon defines the Common Log Format.
+	AccessLogCommon AccessLogFormat = iota
+	// AccessLogCombined defines the Combined Log Format, which extends Common
+	// Log Format with referer and user agent.
+	AccessLogCombined
+	// AccessLogJSON defines a format where each line is a JSON object.
+	AccessLogJSON
+
+	clfTimeFormat = "02/Jan/2006:15:04:05 -0700"
+)
+
+// A AccessLogFormat represents the format of lines written by AccessLog.
+type AccessLogFormat int
+
+// A AccessLog represents a handler that writes a line for each HTTP request.
+type AccessLog struct {
+	output io.Writer
+	format AccessLogFormat
+	mutex  sync.Mutex
+}
+
+// NewAccessLog creates a new AccessLog which writes lines with specified format
+// to output. A nil output defaults to standard error.
+func NewAccessLog(output io.Writer, format AccessLogFormat) *AccessLog {
+	if output == nil {
+		output = os.Stderr
+	}
+	return &AccessLog{
+		output: output,
+		format: format,
+	}
+}
+
+// LogHandler is a HTTP request middleware that writes a line describing the
+// request and its response after next handlers return.
+func (l *AccessLog) LogHandler(next http.Handler) http.Handler {
+	f := func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		r, info := withAuthInfo(r)
+		capture, cw := CaptureResponse(w)
+
+		next.ServeHTTP(cw, r)
+
+		entry := accessLogEntry{
+			Time:      start,
+			Remote:    remoteHost(r.RemoteAddr),
+			User:      info.user,
+			Method:    r.Method,
+			URI:       r.RequestURI,
+			Proto:     r.Proto,
+			Status:    capture.Status(),
+			Size:      capture.Size(),
+			Referer:   r.Referer(),
+			UserAgent: r.UserAgent(),
+			Duration:  time.Since(start).Seconds(),
+		}
+		if entry.Status == 0 {
+			entry.Status = http.StatusOK
+		}
+		if len(entry.URI) == 0 {
+			entry.URI = r.URL.RequestURI()
+		}
+
+		l.write(&entry)
+	}
+
+	return http.HandlerFunc(f)
+}
+
+func (l *AccessLog) write(entry *accessLogEntry) {
+	var buf bytes.Buffer
+	switch l.format {
+	case AccessLogJSON:
+		json.NewEncoder(&buf).Encode(entry)
+	case AccessLogCombined:
+		entry.writeCommon(&buf)
+		fmt.Fprintf(&buf, " %s %s",
+			strconv.Quote(clfField(entry.Referer)),
+			strconv.Quote(clfField(entry.UserAgent)))
+		buf.WriteByte('\n')
+	default:
+		entry.writeCommon(&buf)
+		buf.WriteByte('\n')
+	}
+
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	l.output.Write(buf.Bytes())
+}
+
+// A accessLogEntry represents a line written by AccessLog.
+type accessLogEntry struct {
+	Time      time.Time `json:"time"`
+	Remote    string    `json:"remote"`
+	User      string    `json:"user,omitempty"`
+	Method    string    `json:"method"`
+	URI       string    `json:"uri"`
+	Proto     string    `json:"proto"`
+	Status    int       `json:"status"`
+	Size      int64     `json:"size"`
+	Referer   string    `json:"referer,omitempty"`
+	UserAgent string    `json:"userAgent,omitempty"`
+	Duration  float64   `json:"duration"`
+}
+
+func (e *accessLogEntry) writeCommon(buf *bytes.Buffer) {
+	size := "-"
+	if e.Size > 0 {
+		size = strconv.FormatInt(e.Size, 10)
+	}
+
+	fmt.Fprintf(buf, "%s - %s [%s] %s %d %s",
+		clfField(e.Remote),
+		clfField(e.User),
+		e.Time.Format(clfTimeFormat),
+		strconv.Quote(e.Method+" "+e.URI+" "+e.Proto),
+		e.Status,
+		size)
+}
+
+func clfField(value string) string {
+	if len(value) == 0 {
+		return "-"
+	}
+	return value
+}
+
+func remoteHost(addr string) string {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return addr
+	}
+	return host
+}
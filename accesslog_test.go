@@ -0,0 +1,131 @@
+/*
+ * Copyright 2016 Fabrício Godoy
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package web
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestResponseCapture(t *testing.T) {
+	resp := httptest.NewRecorder()
+	capture, w := CaptureResponse(resp)
+
+	if _, ok := w.(http.Flusher); !ok {
+		t.Error("Flusher interface should be preserved")
+	}
+	if _, ok := w.(http.Hijacker); ok {
+		t.Error("Hijacker interface should not be implemented")
+	}
+	if _, ok := w.(io.ReaderFrom); ok {
+		t.Error("ReaderFrom interface should not be implemented")
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	w.Write([]byte("Lorem ipsum"))
+	w.WriteHeader(http.StatusOK)
+
+	if capture.Status() != http.StatusCreated {
+		t.Errorf("Unexpected status captured: %d", capture.Status())
+	}
+	if capture.Size() != 11 {
+		t.Errorf("Unexpected size captured: %d", capture.Size())
+	}
+
+	capture, w = CaptureResponse(NewUnbufferedResponse(nil))
+	if _, ok := w.(http.Flusher); ok {
+		t.Error("Flusher interface should not be implemented")
+	}
+	if capture.WroteHeader() {
+		t.Error("Headers should not be sent yet")
+	}
+}
+
+func TestAccessLog(t *testing.T) {
+	testValues := []struct {
+		format  AccessLogFormat
+		pattern string
+	}{
+		{AccessLogCommon,
+			`^192\.0\.2\.1 - user \[[^]]+\] "GET /foo\?bar=1 HTTP/1\.1" 201 11\n$`},
+		{AccessLogCombined,
+			`^192\.0\.2\.1 - user \[[^]]+\] "GET /foo\?bar=1 HTTP/1\.1" 201 11 "http://example\.com/" "Tester"\n$`},
+	}
+
+	for _, v := range testValues {
+		var buf bytes.Buffer
+		handler := newAccessLogChain(NewAccessLog(&buf, v.format))
+		handler.ServeHTTP(httptest.NewRecorder(), newAccessLogRequest())
+
+		if !regexp.MustCompile(v.pattern).MatchString(buf.String()) {
+			t.Errorf("Unexpected log line: %s", buf.String())
+		}
+	}
+}
+
+func TestAccessLogCombinedEmptyFields(t *testing.T) {
+	var buf bytes.Buffer
+	handler := newAccessLogChain(NewAccessLog(&buf, AccessLogCombined))
+	req := newAccessLogRequest()
+	req.Header.Del("Referer")
+	req.Header.Del("User-Agent")
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	if !strings.HasSuffix(buf.String(), ` 201 11 "-" "-"`+"\n") {
+		t.Errorf("Empty fields should be written as dashes: %s", buf.String())
+	}
+}
+
+func TestAccessLogJSON(t *testing.T) {
+	var buf bytes.Buffer
+	handler := newAccessLogChain(NewAccessLog(&buf, AccessLogJSON))
+	handler.ServeHTTP(httptest.NewRecorder(), newAccessLogRequest())
+
+	var entry accessLogEntry
+	if err := json.NewDecoder(&buf).Decode(&entry); err != nil {
+		t.Fatalf("Error decoding log line: %v", err)
+	}
+	if entry.Status != http.StatusCreated ||
+		entry.Size != 11 ||
+		entry.User != "user" ||
+		entry.URI != "/foo?bar=1" {
+		t.Errorf("Unexpected log entry: %#v", entry)
+	}
+}
+
+func newAccessLogChain(l *AccessLog) http.Handler {
+	auth := BasicAuthenticator{new(FooAuthenticator)}
+	return NewChain(l.LogHandler, auth.AuthHandler).ThenFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusCreated)
+			io.Copy(w, strings.NewReader("Lorem ipsum"))
+		})
+}
+
+func newAccessLogRequest() *http.Request {
+	req := httptest.NewRequest("GET", "/foo?bar=1", nil)
+	req.Header.Set("Referer", "http://example.com/")
+	req.Header.Set("User-Agent", "Tester")
+	NewHeader().Authorization("user", "secret").Write(req.Header)
+	return req
+}
@@ -17,6 +17,7 @@
 package web
 
 import (
+	"context"
 	"encoding/base64"
 	"net/http"
 )
@@ -31,6 +32,33 @@ type Authenticator interface {
 	AuthHandler(http.Handler) http.Handler
 }
 
+// AuthUser returns the user authenticated by an Authenticator for specified
+// request, or an empty string when request is not authenticated.
+func AuthUser(r *http.Request) string {
+	info, _ := r.Context().Value(authInfoKey).(*authInfo)
+	if info == nil {
+		return ""
+	}
+	return info.user
+}
+
+// An authInfo represents the authentication state of a request. It is stored
+// as pointer into request context, allowing previous middlewares to read the
+// user authenticated by next ones.
+type authInfo struct {
+	user string
+}
+
+// withAuthInfo returns a request whose context has an authInfo.
+func withAuthInfo(r *http.Request) (*http.Request, *authInfo) {
+	info, _ := r.Context().Value(authInfoKey).(*authInfo)
+	if info != nil {
+		return r, info
+	}
+	info = &authInfo{}
+	return r.WithContext(context.WithValue(r.Context(), authInfoKey, info)), info
+}
+
 // WwwAuthenticate creates a HTTP header to require client authentication.
 func (HeaderBuilder) WwwAuthenticate() *Header {
 	return &Header{
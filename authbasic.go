@@ -45,6 +45,8 @@ func (auth BasicAuthenticator) AuthHandler(next http.Handler) http.Handler {
 		if len(user) > 0 &&
 			len(secret) > 0 &&
 			auth.TryAuthentication(r, user, secret) {
+			r, info := withAuthInfo(r)
+			info.user = user
 			next.ServeHTTP(w, r)
 			return
 		}
@@ -0,0 +1,280 @@
+/*
+ * Copyright 2016 Fabrício Godoy
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package web
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// A ETag represents an entity tag, as defined by RFC 7232.
+type ETag struct {
+	// Opaque tag, without quotes.
+	Tag string
+	// Weak defines whether tag is a weak validator.
+	Weak bool
+}
+
+// NewETag creates an entity tag from a hash of specified data.
+func NewETag(data []byte, weak bool) ETag {
+	sum := sha256.Sum256(data)
+	return ETag{
+		Tag:  base64.RawURLEncoding.EncodeToString(sum[:18]),
+		Weak: weak,
+	}
+}
+
+// ParseETags parses specified comma-separated list of entity tags, as used by
+// If-Match and If-None-Match HTTP headers. Malformed tags are ignored.
+//
+// Returns true as second value when list is an asterisk.
+func ParseETags(value string) ([]ETag, bool) {
+	value = strings.TrimSpace(value)
+	if value == "*" {
+		return nil, true
+	}
+
+	result := make([]ETag, 0)
+	for len(value) > 0 {
+		var tag ETag
+		if strings.HasPrefix(value, "W/") {
+			tag.Weak = true
+			value = value[2:]
+		}
+		if len(value) < 2 || value[0] != '"' {
+			break
+		}
+		end := strings.IndexByte(value[1:], '"')
+		if end < 0 {
+			break
+		}
+		tag.Tag = value[1 : end+1]
+		result = append(result, tag)
+
+		value = strings.TrimLeft(value[end+2:], " \t,")
+	}
+	return result, false
+}
+
+// String returns string representation of current instance.
+func (e ETag) String() string {
+	if e.Weak {
+		return `W/"` + e.Tag + `"`
+	}
+	return `"` + e.Tag + `"`
+}
+
+// StrongMatch returns whether both tags are strong and identical.
+func (e ETag) StrongMatch(other ETag) bool {
+	return !e.Weak && !other.Weak && e.Tag == other.Tag
+}
+
+// WeakMatch returns whether both tags are identical, regardless of weakness.
+func (e ETag) WeakMatch(other ETag) bool {
+	return e.Tag == other.Tag
+}
+
+// A CacheControlBuilder provides methods to construct Cache-Control
+// directives.
+type CacheControlBuilder interface {
+	// Build creates and returns the directives as a HTTP header value.
+	Build() string
+
+	// Immutable sets that response will not change while it is fresh.
+	Immutable() CacheControlBuilder
+
+	// MaxAge sets how long response is fresh.
+	MaxAge(time.Duration) CacheControlBuilder
+
+	// MustRevalidate sets that stale response must not be used without
+	// revalidation.
+	MustRevalidate() CacheControlBuilder
+
+	// NoCache sets that response must be revalidated before each use.
+	NoCache() CacheControlBuilder
+
+	// NoStore sets that response must not be stored.
+	NoStore() CacheControlBuilder
+
+	// NoTransform sets that intermediaries must not transform response.
+	NoTransform() CacheControlBuilder
+
+	// Private sets that response must not be stored by shared caches.
+	Private() CacheControlBuilder
+
+	// Public sets that response can be stored by any cache.
+	Public() CacheControlBuilder
+
+	// SMaxAge sets how long response is fresh for shared caches.
+	SMaxAge(time.Duration) CacheControlBuilder
+
+	// StaleWhileRevalidate sets how long a stale response can be used while
+	// it is revalidated in background.
+	StaleWhileRevalidate(time.Duration) CacheControlBuilder
+}
+
+type cacheControlBuilder struct {
+	directives []string
+}
+
+// NewCacheControl creates a new instance of CacheControlBuilder.
+func NewCacheControl() CacheControlBuilder {
+	return &cacheControlBuilder{}
+}
+
+func (b *cacheControlBuilder) Build() string {
+	return strings.Join(b.directives, ", ")
+}
+
+func (b *cacheControlBuilder) Immutable() CacheControlBuilder {
+	return b.add("immutable")
+}
+
+func (b *cacheControlBuilder) MaxAge(d time.Duration) CacheControlBuilder {
+	return b.addSeconds("max-age", d)
+}
+
+func (b *cacheControlBuilder) MustRevalidate() CacheControlBuilder {
+	return b.add("must-revalidate")
+}
+
+func (b *cacheControlBuilder) NoCache() CacheControlBuilder {
+	return b.add("no-cache")
+}
+
+func (b *cacheControlBuilder) NoStore() CacheControlBuilder {
+	return b.add("no-store")
+}
+
+func (b *cacheControlBuilder) NoTransform() CacheControlBuilder {
+	return b.add("no-transform")
+}
+
+func (b *cacheControlBuilder) Private() CacheControlBuilder {
+	return b.add("private")
+}
+
+func (b *cacheControlBuilder) Public() CacheControlBuilder {
+	return b.add("public")
+}
+
+func (b *cacheControlBuilder) SMaxAge(d time.Duration) CacheControlBuilder {
+	return b.addSeconds("s-maxage", d)
+}
+
+func (b *cacheControlBuilder) StaleWhileRevalidate(
+	d time.Duration,
+) CacheControlBuilder {
+	return b.addSeconds("stale-while-revalidate", d)
+}
+
+func (b *cacheControlBuilder) add(directive string) CacheControlBuilder {
+	b.directives = append(b.directives, directive)
+	return b
+}
+
+func (b *cacheControlBuilder) addSeconds(
+	directive string,
+	d time.Duration,
+) CacheControlBuilder {
+	return b.add(directive + "=" + strconv.FormatInt(int64(d/time.Second), 10))
+}
+
+// NotModified returns whether client already has current version of resource,
+// as defined by If-None-Match and If-Modified-Since HTTP headers of specified
+// request. A zero etag or lastModified is ignored.
+//
+// Only GET and HEAD requests can be not modified.
+func NotModified(r *http.Request, etag ETag, lastModified time.Time) bool {
+	if r.Method != "GET" && r.Method != "HEAD" {
+		return false
+	}
+
+	inm := NewHeader().IfNoneMatch().ReadAll(r.Header).Value
+	if len(inm) > 0 {
+		tags, any := ParseETags(inm)
+		if any {
+			return true
+		}
+		if len(etag.Tag) == 0 {
+			return false
+		}
+		for _, v := range tags {
+			if v.WeakMatch(etag) {
+				return true
+			}
+		}
+		return false
+	}
+
+	if lastModified.IsZero() {
+		return false
+	}
+	since, err := NewHeader().IfModifiedSince().Read(r.Header).Time()
+	if err != nil {
+		return false
+	}
+	return !lastModified.Truncate(time.Second).After(since)
+}
+
+// JSONWriteConditional serializes defined content to JSON format, computes an
+// entity tag of serialized data and writes it as JSONWrite does. A Not
+// Modified (304) status without body is written instead when client already
+// has current version, as defined by NotModified.
+//
+// A Last-Modified HTTP header already defined on response is honoured. An
+// Internal Server Error (500) JSONError is written when content cannot be
+// serialized, as JSONWriteBuffered does.
+func JSONWriteConditional(
+	w http.ResponseWriter,
+	r *http.Request,
+	status int,
+	content interface{},
+	weak bool,
+) error {
+	content, contentType := prepareJSONContent(w, content)
+	var data []byte
+	if content != nil {
+		var err error
+		if data, err = marshalJSON(content); err != nil {
+			writeError(w, NewJSONError().FromError(err).Build())
+			return err
+		}
+	}
+
+	etag := NewETag(data, weak)
+	NewHeader().ETag().SetValue(etag.String()).Write(w.Header())
+
+	lastModified, _ := NewHeader().LastModified().Read(w.Header()).Time()
+	if status >= 200 && status < 300 && NotModified(r, etag, lastModified) {
+		w.Header().Del(headerNameContentType)
+		w.WriteHeader(http.StatusNotModified)
+		return nil
+	}
+
+	contentType.Write(w.Header())
+	NewHeader().ContentLength().SetInt(int64(len(data))).Write(w.Header())
+	w.WriteHeader(status)
+	_, err := w.Write(data)
+	return err
+}
+
+var _ CacheControlBuilder = (*cacheControlBuilder)(nil)
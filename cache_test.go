@@ -0,0 +1,160 @@
+/*
+ * Copyright 2016 Fabrício Godoy
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package web
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestParseETags(t *testing.T) {
+	tags, any := ParseETags(`"abc", W/"def" ,"g,h"`)
+	if any {
+		t.Error("List should not be an asterisk")
+	}
+
+	expected := []ETag{{"abc", false}, {"def", true}, {"g,h", false}}
+	if len(tags) != len(expected) {
+		t.Fatalf("Unexpected entity tags: %v", tags)
+	}
+	for i, v := range expected {
+		if tags[i] != v {
+			t.Errorf("Unexpected entity tag #%d: %v", i, tags[i])
+		}
+	}
+
+	if _, any := ParseETags(" * "); !any {
+		t.Error("Asterisk should be parsed")
+	}
+	if tags[1].String() != `W/"def"` {
+		t.Errorf("Unexpected weak tag string: %s", tags[1].String())
+	}
+	if tags[1].StrongMatch(ETag{"def", false}) ||
+		!tags[1].WeakMatch(ETag{"def", false}) {
+		t.Error("Unexpected weak tag comparison")
+	}
+}
+
+func TestCacheControlBuilder(t *testing.T) {
+	value := NewCacheControl().
+		Public().
+		MaxAge(time.Hour).
+		SMaxAge(10 * time.Minute).
+		MustRevalidate().
+		Build()
+	if value != "public, max-age=3600, s-maxage=600, must-revalidate" {
+		t.Errorf("Unexpected Cache-Control value: %s", value)
+	}
+}
+
+func TestJSONWriteConditional(t *testing.T) {
+	foo := Foo{Number: 40, Text: "Lorem ipsum"}
+
+	resp := httptest.NewRecorder()
+	req := httptest.NewRequest("GET", "/", nil)
+	if err := JSONWriteConditional(resp, req, http.StatusOK, foo, false); err != nil {
+		t.Fatalf("Error on JSONWriteConditional: %v", err)
+	}
+	etag := resp.Header().Get("ETag")
+	if resp.Code != http.StatusOK || len(etag) == 0 {
+		t.Fatalf("Unexpected response: %d (%s)", resp.Code, etag)
+	}
+	var fooCopy Foo
+	if err := json.NewDecoder(resp.Body).Decode(&fooCopy); err != nil ||
+		!foo.IsEqual(fooCopy) {
+		t.Errorf("Unexpected content: %s", resp.Body.String())
+	}
+
+	testValues := []struct {
+		method      string
+		ifNoneMatch string
+		status      int
+	}{
+		{"GET", etag, http.StatusNotModified},
+		{"GET", `"other", W/` + etag, http.StatusNotModified},
+		{"HEAD", "*", http.StatusNotModified},
+		{"GET", `"other"`, http.StatusOK},
+		{"POST", etag, http.StatusOK},
+	}
+	for _, v := range testValues {
+		resp := httptest.NewRecorder()
+		req := httptest.NewRequest(v.method, "/", nil)
+		NewHeader().IfNoneMatch().SetValue(v.ifNoneMatch).Write(req.Header)
+		JSONWriteConditional(resp, req, http.StatusOK, foo, false)
+
+		if resp.Code != v.status {
+			t.Errorf("Unexpected status for %s '%s': %d",
+				v.method, v.ifNoneMatch, resp.Code)
+		}
+		if resp.Code == http.StatusNotModified && resp.Body.Len() > 0 {
+			t.Errorf("Not modified response should not have body: %s",
+				resp.Body.String())
+		}
+		if resp.Header().Get("ETag") != etag {
+			t.Errorf("Unexpected entity tag: %s", resp.Header().Get("ETag"))
+		}
+	}
+}
+
+func TestJSONWriteConditionalErrors(t *testing.T) {
+	resp := httptest.NewRecorder()
+	NewHeader().RequestID().SetValue("rid").Write(resp.Header())
+	req := httptest.NewRequest("GET", "/", nil)
+	jerr := NewJSONError().Status(http.StatusNotFound).Build()
+	JSONWriteConditional(resp, req, jerr.Status, jerr, false)
+
+	var jerrCopy JSONError
+	if err := json.Unmarshal(resp.Body.Bytes(), &jerrCopy); err != nil ||
+		jerrCopy.RequestID != "rid" {
+		t.Errorf("Unexpected JSONError content: %s", resp.Body.String())
+	}
+
+	resp = httptest.NewRecorder()
+	problem := &ProblemDetails{Status: http.StatusNotFound}
+	JSONWriteConditional(resp, req, problem.Status, problem, false)
+	if ct := resp.Header().Get("Content-Type"); ct !=
+		NewHeader().ContentType().ProblemJSON().Value {
+		t.Errorf("Unexpected content type: %s", ct)
+	}
+}
+
+func TestJSONWriteConditionalLastModified(t *testing.T) {
+	modified := time.Date(2016, 3, 1, 10, 0, 0, 0, time.UTC)
+	testValues := []struct {
+		since  time.Time
+		status int
+	}{
+		{modified, http.StatusNotModified},
+		{modified.Add(time.Hour), http.StatusNotModified},
+		{modified.Add(-time.Second), http.StatusOK},
+	}
+
+	for _, v := range testValues {
+		resp := httptest.NewRecorder()
+		NewHeader().LastModified().SetTime(modified).Write(resp.Header())
+		req := httptest.NewRequest("GET", "/", nil)
+		NewHeader().IfModifiedSince().SetTime(v.since).Write(req.Header)
+		JSONWriteConditional(resp, req, http.StatusOK, Foo{}, true)
+
+		if resp.Code != v.status {
+			t.Errorf("Unexpected status for '%v': %d", v.since, resp.Code)
+		}
+	}
+}
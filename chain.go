@@ -28,9 +28,24 @@ type MiddlewareFunc func(http.Handler) http.Handler
 // HTTP handler.
 type Chain []MiddlewareFunc
 
-// NewChain creates a new empty slice of MiddlewareFunc.
-func NewChain() Chain {
-	return make(Chain, 0)
+// NewChain creates a new slice of MiddlewareFunc containing specified
+// middlewares.
+func NewChain(middlewares ...MiddlewareFunc) Chain {
+	return append(make(Chain, 0, len(middlewares)), middlewares...)
+}
+
+// Append returns a new chain containing current middlewares followed by
+// specified ones. Current instance is never modified.
+func (s Chain) Append(middlewares ...MiddlewareFunc) Chain {
+	result := make(Chain, 0, len(s)+len(middlewares))
+	result = append(result, s...)
+	return append(result, middlewares...)
+}
+
+// Extend returns a new chain containing current middlewares followed by
+// middlewares of specified chain. Neither chain is modified.
+func (s Chain) Extend(chain Chain) Chain {
+	return s.Append(chain...)
 }
 
 // Get returns a HTTP handler which is a chain of middlewares and then the
@@ -41,3 +56,21 @@ func (s Chain) Get(handler http.Handler) http.Handler {
 	}
 	return handler
 }
+
+// Then returns a HTTP handler which is a chain of middlewares and then the
+// specified handler. A nil handler defaults to http.DefaultServeMux.
+func (s Chain) Then(handler http.Handler) http.Handler {
+	if handler == nil {
+		handler = http.DefaultServeMux
+	}
+	return s.Get(handler)
+}
+
+// ThenFunc returns a HTTP handler which is a chain of middlewares and then the
+// specified handler function.
+func (s Chain) ThenFunc(fn http.HandlerFunc) http.Handler {
+	if fn == nil {
+		return s.Then(nil)
+	}
+	return s.Get(fn)
+}
@@ -65,3 +65,64 @@ func (h FooHandler) Middleware(next http.Handler) http.Handler {
 func (h FooHandler) EndPoint(w http.ResponseWriter, r *http.Request) {
 	stacker = append(stacker, int(h))
 }
+
+func TestChainAppend(t *testing.T) {
+	stacker = make([]int, 0)
+
+	base := make(Chain, 0, 8).Append(FooHandler(1).Middleware)
+	c1 := base.Append(FooHandler(2).Middleware)
+	c2 := base.Append(FooHandler(3).Middleware)
+
+	if len(base) != 1 {
+		t.Errorf("Base chain was modified: %d middlewares", len(base))
+	}
+
+	c1.ThenFunc(FooHandler(9).EndPoint).ServeHTTP(nil, nil)
+	expected := []int{1, 2, 9}
+	if !equalInts(stacker, expected) {
+		t.Errorf("Unexpected calls from first variant: %v instead of %v",
+			stacker, expected)
+	}
+
+	stacker = make([]int, 0)
+	c2.ThenFunc(FooHandler(9).EndPoint).ServeHTTP(nil, nil)
+	expected = []int{1, 3, 9}
+	if !equalInts(stacker, expected) {
+		t.Errorf("Unexpected calls from second variant: %v instead of %v",
+			stacker, expected)
+	}
+}
+
+func TestChainExtend(t *testing.T) {
+	stacker = make([]int, 0)
+
+	base := NewChain(FooHandler(1).Middleware, FooHandler(2).Middleware)
+	other := NewChain(FooHandler(3).Middleware)
+	c1 := base.Extend(other)
+	c2 := base.Extend(NewChain(FooHandler(4).Middleware))
+	c1[0] = FooHandler(5).Middleware
+
+	if len(base) != 2 || len(other) != 1 {
+		t.Errorf("Source chains were modified: %d and %d middlewares",
+			len(base), len(other))
+	}
+
+	c2.Then(http.HandlerFunc(FooHandler(9).EndPoint)).ServeHTTP(nil, nil)
+	expected := []int{1, 2, 4, 9}
+	if !equalInts(stacker, expected) {
+		t.Errorf("Unexpected calls from extended chain: %v instead of %v",
+			stacker, expected)
+	}
+}
+
+func equalInts(a, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,200 @@
+/*
+ * Copyright 2016 Fabrício Godoy
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package web
+
+import (
+	"context"
+	"net/http"
+	"reflect"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+)
+
+// namedFuncPtr holds the code pointer of middlewares created by Named
+// function. Named is not inlined, so every middleware it creates shares the
+// code pointer of a single closure.
+var namedFuncPtr = reflect.ValueOf(Named("", nil)).Pointer()
+
+// A nameProbe represents a HTTP handler used to retrieve the name of a
+// middleware created by Named function.
+type nameProbe struct {
+	name  string
+	named bool
+}
+
+func (*nameProbe) ServeHTTP(http.ResponseWriter, *http.Request) {}
+
+// Named returns a middleware which behaves exactly as specified middleware and
+// is identified by specified name when its chain is described.
+//
+//go:noinline
+func Named(name string, mw MiddlewareFunc) MiddlewareFunc {
+	return func(next http.Handler) http.Handler {
+		if probe, ok := next.(*nameProbe); ok && !probe.named {
+			probe.name, probe.named = name, true
+			return probe
+		}
+		return mw(next)
+	}
+}
+
+// MiddlewareName returns the name of specified middleware. Middlewares which
+// was not created by Named function are identified by its function name.
+func MiddlewareName(mw MiddlewareFunc) string {
+	ptr := reflect.ValueOf(mw).Pointer()
+	if ptr == namedFuncPtr {
+		probe := &nameProbe{}
+		mw(probe)
+		if probe.named {
+			return probe.name
+		}
+	}
+
+	fn := runtime.FuncForPC(ptr)
+	if fn == nil {
+		return ""
+	}
+	name := fn.Name()
+	if i := strings.LastIndex(name, "/"); i >= 0 {
+		name = name[i+1:]
+	}
+	return name
+}
+
+// Describe returns the names of current middlewares in order.
+func (s Chain) Describe() []string {
+	result := make([]string, len(s))
+	for i, v := range s {
+		result[i] = MiddlewareName(v)
+	}
+	return result
+}
+
+// DescribeHandler returns a HTTP handler which writes the names of current
+// middlewares as JSON.
+func (s Chain) DescribeHandler() http.Handler {
+	names := s.Describe()
+	f := func(w http.ResponseWriter, r *http.Request) {
+		JSONWrite(w, http.StatusOK, names)
+	}
+
+	return http.HandlerFunc(f)
+}
+
+// Debug returns a HTTP handler which is a chain of middlewares and then the
+// specified handler, recording entry and exit times of each middleware into a
+// ChainTrace stored in request context.
+//
+// The done function, when defined, is called with the complete trace after
+// the chain returns.
+func (s Chain) Debug(
+	handler http.Handler,
+	done func(r *http.Request, trace *ChainTrace),
+) http.Handler {
+	for i := len(s) - 1; i >= 0; i-- {
+		handler = &traceHandler{
+			name: MiddlewareName(s[i]),
+			next: s[i](handler),
+		}
+	}
+
+	f := func(w http.ResponseWriter, r *http.Request) {
+		trace := ChainTraceFrom(r)
+		if trace == nil {
+			trace = &ChainTrace{}
+			r = r.WithContext(
+				context.WithValue(r.Context(), chainTraceKey, trace))
+		}
+		handler.ServeHTTP(w, r)
+		if done != nil {
+			done(r, trace)
+		}
+	}
+
+	return http.HandlerFunc(f)
+}
+
+// A ChainTraceEntry represents the timing of a single middleware call.
+type ChainTraceEntry struct {
+	// Name of middleware.
+	Name string `json:"name"`
+	// Time when middleware was entered.
+	Enter time.Time `json:"enter"`
+	// Time when middleware returned; zero while it is running.
+	Exit time.Time `json:"exit"`
+}
+
+// Duration returns how long middleware took to return, including the time
+// spent by next handlers.
+func (e ChainTraceEntry) Duration() time.Duration {
+	if e.Exit.IsZero() {
+		return 0
+	}
+	return e.Exit.Sub(e.Enter)
+}
+
+// A ChainTrace represents timings recorded by a chain in debug mode.
+type ChainTrace struct {
+	mutex   sync.Mutex
+	entries []ChainTraceEntry
+}
+
+// ChainTraceFrom returns the ChainTrace stored into specified request context,
+// or nil when the request was not handled by a chain in debug mode.
+func ChainTraceFrom(r *http.Request) *ChainTrace {
+	trace, _ := r.Context().Value(chainTraceKey).(*ChainTrace)
+	return trace
+}
+
+// Entries returns a copy of entries recorded so far.
+func (t *ChainTrace) Entries() []ChainTraceEntry {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	return append([]ChainTraceEntry(nil), t.entries...)
+}
+
+func (t *ChainTrace) enter(name string) int {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	t.entries = append(t.entries, ChainTraceEntry{
+		Name:  name,
+		Enter: time.Now(),
+	})
+	return len(t.entries) - 1
+}
+
+func (t *ChainTrace) exit(index int) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	t.entries[index].Exit = time.Now()
+}
+
+// A traceHandler represents a HTTP handler which records timings of a
+// middleware.
+type traceHandler struct {
+	name string
+	next http.Handler
+}
+
+func (h *traceHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	trace := ChainTraceFrom(r)
+	index := trace.enter(h.name)
+	defer trace.exit(index)
+	h.next.ServeHTTP(w, r)
+}
@@ -0,0 +1,143 @@
+/*
+ * Copyright 2016 Fabrício Godoy
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package web
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestChainDescribe(t *testing.T) {
+	chain := NewChain(
+		Named("first", FooHandler(1).Middleware),
+		FooHandler(2).Middleware,
+		Named("third", FooHandler(3).Middleware),
+	)
+
+	names := chain.Describe()
+	if len(names) != 3 {
+		t.Fatalf("Unexpected names length: %v", names)
+	}
+	if names[0] != "first" || names[2] != "third" {
+		t.Errorf("Unexpected middleware names: %v", names)
+	}
+	if !strings.Contains(names[1], "FooHandler.Middleware") {
+		t.Errorf("Unnamed middleware should be identified by function: %s",
+			names[1])
+	}
+
+	stacker = make([]int, 0)
+	chain.ThenFunc(FooHandler(4).EndPoint).ServeHTTP(nil, nil)
+	if !equalInts(stacker, []int{1, 2, 3, 4}) {
+		t.Errorf("Named middlewares should be called in order: %v", stacker)
+	}
+
+	resp := httptest.NewRecorder()
+	chain.DescribeHandler().ServeHTTP(resp, httptest.NewRequest("GET", "/", nil))
+	var dump []string
+	if err := json.NewDecoder(resp.Body).Decode(&dump); err != nil {
+		t.Fatalf("Error decoding chain description: %v", err)
+	}
+	if !equalStrings(dump, names) {
+		t.Errorf("Unexpected chain description: %v", dump)
+	}
+}
+
+func TestChainDebug(t *testing.T) {
+	var inner, trace *ChainTrace
+	chain := NewChain(
+		Named("first", FooHandler(1).Middleware),
+		Named("second", FooHandler(2).Middleware),
+	)
+	handler := chain.Debug(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			inner = ChainTraceFrom(r)
+		}),
+		func(r *http.Request, t *ChainTrace) {
+			trace = t
+		})
+
+	stacker = make([]int, 0)
+	req := httptest.NewRequest("GET", "/", nil)
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	if ChainTraceFrom(req) != nil {
+		t.Error("Original request should not be modified")
+	}
+	if trace == nil || trace != inner {
+		t.Fatal("Debug mode should store a trace into request context")
+	}
+
+	entries := trace.Entries()
+	if len(entries) != 2 {
+		t.Fatalf("Unexpected trace entries: %v", entries)
+	}
+	for i, name := range []string{"first", "second"} {
+		if entries[i].Name != name {
+			t.Errorf("Unexpected trace entry name: %s", entries[i].Name)
+		}
+		if entries[i].Exit.IsZero() ||
+			entries[i].Exit.Before(entries[i].Enter) {
+			t.Errorf("Invalid timing for '%s': %v", name, entries[i])
+		}
+	}
+	if entries[1].Enter.Before(entries[0].Enter) {
+		t.Error("Trace entries are out of order")
+	}
+}
+
+func TestMiddlewareNameNested(t *testing.T) {
+	mw := Named("outer", Named("inner", FooHandler(1).Middleware))
+	if name := MiddlewareName(mw); name != "outer" {
+		t.Errorf("Unexpected middleware name: %s", name)
+	}
+	if name := MiddlewareName(Named("", FooHandler(1).Middleware)); name != "" {
+		t.Errorf("Empty name should be kept: %s", name)
+	}
+}
+
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestMiddlewareNameWrapped(t *testing.T) {
+	calls := 0
+	anonymous := func(next http.Handler) http.Handler {
+		calls++
+		return next
+	}
+	if name := MiddlewareName(anonymous); len(name) == 0 || calls != 0 {
+		t.Errorf("Unnamed middleware should not be called: %s (%d calls)",
+			name, calls)
+	}
+
+	mw := When(PathPrefix("/x"), Named("auth", FooHandler(1).Middleware))
+	if name := MiddlewareName(mw); name == "auth" {
+		t.Error("Wrapped named middleware should not hide its condition")
+	}
+}
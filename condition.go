@@ -0,0 +1,106 @@
+/*
+ * Copyright 2016 Fabrício Godoy
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package web
+
+import (
+	"net/http"
+	"path"
+	"strings"
+)
+
+// A RequestPredicate represents a function that tests a HTTP request.
+type RequestPredicate func(*http.Request) bool
+
+// When returns a middleware which calls specified middleware only when the
+// predicate matches the request; otherwise, next handler is called directly.
+func When(pred RequestPredicate, mw MiddlewareFunc) MiddlewareFunc {
+	return func(next http.Handler) http.Handler {
+		wrapped := mw(next)
+		f := func(w http.ResponseWriter, r *http.Request) {
+			if pred(r) {
+				wrapped.ServeHTTP(w, r)
+				return
+			}
+			next.ServeHTTP(w, r)
+		}
+
+		return http.HandlerFunc(f)
+	}
+}
+
+// Unless returns a middleware which calls specified middleware only when the
+// predicate does not match the request.
+func Unless(pred RequestPredicate, mw MiddlewareFunc) MiddlewareFunc {
+	return When(Not(pred), mw)
+}
+
+// Not returns a predicate which negates specified predicate.
+func Not(pred RequestPredicate) RequestPredicate {
+	return func(r *http.Request) bool {
+		return !pred(r)
+	}
+}
+
+// PathPrefix returns a predicate which matches requests whose URL path starts
+// with any of specified prefixes.
+func PathPrefix(prefixes ...string) RequestPredicate {
+	return func(r *http.Request) bool {
+		for _, v := range prefixes {
+			if strings.HasPrefix(r.URL.Path, v) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// PathGlob returns a predicate which matches requests whose URL path matches
+// any of specified shell patterns, as defined by path.Match.
+//
+// Malformed patterns never match.
+func PathGlob(patterns ...string) RequestPredicate {
+	return func(r *http.Request) bool {
+		for _, v := range patterns {
+			if ok, _ := path.Match(v, r.URL.Path); ok {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// MethodIn returns a predicate which matches requests using any of specified
+// HTTP methods.
+func MethodIn(methods ...string) RequestPredicate {
+	return func(r *http.Request) bool {
+		for _, v := range methods {
+			if strings.EqualFold(r.Method, v) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// HasHeader returns a predicate which matches requests that include specified
+// HTTP header.
+func HasHeader(name string) RequestPredicate {
+	return func(r *http.Request) bool {
+		_, ok := r.Header[http.CanonicalHeaderKey(name)]
+		return ok
+	}
+}
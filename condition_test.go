@@ -0,0 +1,89 @@
+/*
+ * Copyright 2016 Fabrício Godoy
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package web
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestConditionalMiddleware(t *testing.T) {
+	testValues := []struct {
+		mw       MiddlewareFunc
+		method   string
+		url      string
+		header   string
+		expected []int
+	}{
+		{When(PathPrefix("/api"), FooHandler(1).Middleware),
+			"GET", "/api/users", "", []int{1, 9}},
+		{When(PathPrefix("/api"), FooHandler(1).Middleware),
+			"GET", "/healthz", "", []int{9}},
+		{Unless(PathPrefix("/healthz"), FooHandler(1).Middleware),
+			"GET", "/healthz", "", []int{9}},
+		{Unless(PathPrefix("/healthz"), FooHandler(1).Middleware),
+			"GET", "/api", "", []int{1, 9}},
+		{When(PathGlob("/users/*/avatar"), FooHandler(1).Middleware),
+			"GET", "/users/42/avatar", "", []int{1, 9}},
+		{When(PathGlob("/users/*/avatar"), FooHandler(1).Middleware),
+			"GET", "/users/42/name", "", []int{9}},
+		{When(MethodIn("POST", "PUT"), FooHandler(1).Middleware),
+			"POST", "/", "", []int{1, 9}},
+		{When(MethodIn("POST", "PUT"), FooHandler(1).Middleware),
+			"GET", "/", "", []int{9}},
+		{When(HasHeader("x-debug"), FooHandler(1).Middleware),
+			"GET", "/", "X-Debug", []int{1, 9}},
+		{When(HasHeader("x-debug"), FooHandler(1).Middleware),
+			"GET", "/", "", []int{9}},
+	}
+
+	for i, v := range testValues {
+		stacker = make([]int, 0)
+		req := httptest.NewRequest(v.method, v.url, nil)
+		if len(v.header) > 0 {
+			req.Header.Set(v.header, "1")
+		}
+
+		NewChain(v.mw).
+			ThenFunc(FooHandler(9).EndPoint).
+			ServeHTTP(httptest.NewRecorder(), req)
+		if !equalInts(stacker, v.expected) {
+			t.Errorf("Unexpected calls on test #%d: %v instead of %v",
+				i, stacker, v.expected)
+		}
+	}
+}
+
+func TestConditionalAuthenticator(t *testing.T) {
+	auth := BasicAuthenticator{new(FooAuthenticator)}
+	chain := NewChain(Unless(PathPrefix("/healthz"), auth.AuthHandler))
+	handler := chain.ThenFunc(func(w http.ResponseWriter, r *http.Request) {})
+
+	resp := httptest.NewRecorder()
+	handler.ServeHTTP(resp, httptest.NewRequest("GET", "/healthz", nil))
+	if resp.Code != http.StatusOK {
+		t.Errorf("Health check should not require authentication: %d",
+			resp.Code)
+	}
+
+	resp = httptest.NewRecorder()
+	handler.ServeHTTP(resp, httptest.NewRequest("GET", "/api", nil))
+	if resp.Code != http.StatusUnauthorized {
+		t.Errorf("API should require authentication: %d", resp.Code)
+	}
+}
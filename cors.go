@@ -0,0 +1,185 @@
+/*
+ * Copyright 2016 Fabrício Godoy
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package web
+
+import (
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// A CORS represents a handler for Cross-Origin Resource Sharing.
+type CORS struct {
+	// AllowedOrigins defines which origins are allowed. An origin can contain
+	// a single asterisk as wildcard for host names, as in
+	// "https://*.example.com", and a single asterisk allows any origin.
+	AllowedOrigins []string
+	// AllowedOriginPatterns defines regular expressions matching allowed
+	// origins. A pattern must match the whole origin, as if it was anchored.
+	AllowedOriginPatterns []*regexp.Regexp
+	// AllowedMethods defines which HTTP methods are allowed. Defaults to GET,
+	// HEAD and POST.
+	AllowedMethods []string
+	// AllowedHeaders defines which HTTP headers can be sent by client. A single
+	// asterisk allows any header.
+	AllowedHeaders []string
+	// ExposedHeaders defines which HTTP headers can be read by client.
+	ExposedHeaders []string
+	// AllowCredentials defines whether client can send credentials.
+	AllowCredentials bool
+	// MaxAge defines how long preflight results can be cached.
+	MaxAge time.Duration
+}
+
+// CORSHandler is a HTTP request middleware that answers preflight requests and
+// adds CORS headers to responses to allowed origins.
+//
+// Panics when any origin is allowed together with credentials.
+func (c CORS) CORSHandler(next http.Handler) http.Handler {
+	patterns := make([]*regexp.Regexp, len(c.AllowedOriginPatterns))
+	for i, v := range c.AllowedOriginPatterns {
+		patterns[i] = regexp.MustCompile(`^(?:` + v.String() + `)$`)
+	}
+	c.AllowedOriginPatterns = patterns
+
+	anyOrigin := false
+	for _, v := range c.AllowedOrigins {
+		if v == "*" {
+			anyOrigin = true
+		}
+	}
+	if anyOrigin && c.AllowCredentials {
+		panic("CORS cannot allow any origin together with credentials")
+	}
+
+	methods := c.AllowedMethods
+	if len(methods) == 0 {
+		methods = []string{"GET", "HEAD", "POST"}
+	}
+	anyHeader := false
+	for _, v := range c.AllowedHeaders {
+		if v == "*" {
+			anyHeader = true
+		}
+	}
+
+	f := func(w http.ResponseWriter, r *http.Request) {
+		origin := NewHeader().Origin().Read(r.Header).Value
+		reqMethod := NewHeader().AccessControlRequestMethod().Read(r.Header).Value
+		preflight := r.Method == "OPTIONS" && len(reqMethod) > 0
+
+		NewHeader().Vary().SetList("Origin").Append(w.Header())
+		if preflight {
+			NewHeader().Vary().
+				SetList("Access-Control-Request-Method",
+					"Access-Control-Request-Headers").
+				Append(w.Header())
+		}
+
+		if len(origin) == 0 {
+			next.ServeHTTP(w, r)
+			return
+		}
+		if !c.isOriginAllowed(origin) {
+			if preflight {
+				w.WriteHeader(http.StatusForbidden)
+				return
+			}
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		allowOrigin := origin
+		if anyOrigin {
+			allowOrigin = "*"
+		}
+		NewHeader().AccessControlAllowOrigin().
+			SetValue(allowOrigin).
+			Write(w.Header())
+		if c.AllowCredentials {
+			NewHeader().AccessControlAllowCredentials().
+				SetBool(true).
+				Write(w.Header())
+		}
+
+		if !preflight {
+			if len(c.ExposedHeaders) > 0 {
+				NewHeader().AccessControlExposeHeaders().
+					SetList(c.ExposedHeaders...).
+					Write(w.Header())
+			}
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		reqHeaders := NewHeader().AccessControlRequestHeaders().
+			Read(r.Header).
+			List()
+		if !containsFold(methods, reqMethod) ||
+			(!anyHeader && !containsAllFold(c.AllowedHeaders, reqHeaders)) {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+
+		NewHeader().AccessControlAllowMethods().
+			SetList(methods...).
+			Write(w.Header())
+		if len(reqHeaders) > 0 {
+			NewHeader().AccessControlAllowHeaders().
+				SetList(reqHeaders...).
+				Write(w.Header())
+		}
+		if c.MaxAge > 0 {
+			NewHeader().AccessControlMaxAge().
+				SetDuration(c.MaxAge).
+				Write(w.Header())
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}
+
+	return http.HandlerFunc(f)
+}
+
+func (c CORS) isOriginAllowed(origin string) bool {
+	for _, v := range c.AllowedOrigins {
+		if v == "*" || v == origin {
+			return true
+		}
+
+		i := strings.IndexByte(v, '*')
+		if i < 0 {
+			continue
+		}
+		prefix, suffix := v[:i], v[i+1:]
+		if len(origin) > len(prefix)+len(suffix) &&
+			strings.HasPrefix(origin, prefix) &&
+			strings.HasSuffix(origin, suffix) &&
+			!strings.ContainsAny(
+				origin[len(prefix):len(origin)-len(suffix)], "/:@") {
+			return true
+		}
+	}
+
+	for _, v := range c.AllowedOriginPatterns {
+		if v.MatchString(origin) {
+			return true
+		}
+	}
+
+	return false
+}
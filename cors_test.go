@@ -0,0 +1,154 @@
+/*
+ * Copyright 2016 Fabrício Godoy
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package web
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+	"time"
+)
+
+func TestCORSOrigin(t *testing.T) {
+	c := CORS{
+		AllowedOrigins: []string{
+			"https://example.com",
+			"https://*.example.org",
+		},
+		AllowedOriginPatterns: []*regexp.Regexp{
+			regexp.MustCompile(`^http://localhost:\d+$`),
+			regexp.MustCompile(`https://.*\.example\.com`),
+		},
+		ExposedHeaders:   []string{"X-Request-ID"},
+		AllowCredentials: true,
+	}
+	testValues := []struct {
+		origin  string
+		allowed bool
+	}{
+		{"https://example.com", true},
+		{"http://example.com", false},
+		{"https://api.example.org", true},
+		{"https://example.org", false},
+		{"https://evil.com/.example.org", false},
+		{"http://localhost:8080", true},
+		{"http://localhost", false},
+		{"https://a.example.com", true},
+		{"https://a.example.com.evil.net", false},
+		{"http://evil.net/https://a.example.com", false},
+	}
+
+	handler := NewChain(c.CORSHandler).ThenFunc(
+		func(w http.ResponseWriter, r *http.Request) {})
+	for _, v := range testValues {
+		req := httptest.NewRequest("GET", "/", nil)
+		NewHeader().Origin().SetValue(v.origin).Write(req.Header)
+		resp := httptest.NewRecorder()
+		handler.ServeHTTP(resp, req)
+
+		allowOrigin := resp.Header().Get("Access-Control-Allow-Origin")
+		if v.allowed != (allowOrigin == v.origin) {
+			t.Errorf("Unexpected allowed origin for '%s': '%s'",
+				v.origin, allowOrigin)
+		}
+		if resp.Header().Get("Vary") != "Origin" {
+			t.Errorf("Vary header should be defined: %v", resp.Header())
+		}
+		if v.allowed &&
+			resp.Header().Get("Access-Control-Expose-Headers") != "X-Request-ID" {
+			t.Errorf("Exposed headers should be defined: %v", resp.Header())
+		}
+	}
+}
+
+func TestCORSPreflight(t *testing.T) {
+	called := false
+	c := CORS{
+		AllowedOrigins: []string{"*"},
+		AllowedMethods: []string{"GET", "PUT"},
+		AllowedHeaders: []string{"Content-Type"},
+		MaxAge:         10 * time.Minute,
+	}
+	handler := NewChain(c.CORSHandler).ThenFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			called = true
+		})
+
+	testValues := []struct {
+		method  string
+		headers string
+		status  int
+	}{
+		{"PUT", "content-type", http.StatusNoContent},
+		{"DELETE", "", http.StatusForbidden},
+		{"GET", "X-Custom", http.StatusForbidden},
+	}
+
+	for _, v := range testValues {
+		req := httptest.NewRequest("OPTIONS", "/", nil)
+		NewHeader().Origin().SetValue("https://example.com").Write(req.Header)
+		NewHeader().AccessControlRequestMethod().
+			SetValue(v.method).
+			Write(req.Header)
+		if len(v.headers) > 0 {
+			NewHeader().AccessControlRequestHeaders().
+				SetValue(v.headers).
+				Write(req.Header)
+		}
+		resp := httptest.NewRecorder()
+		handler.ServeHTTP(resp, req)
+
+		if resp.Code != v.status {
+			t.Errorf("Unexpected status for %s preflight: %d",
+				v.method, resp.Code)
+		}
+	}
+
+	if called {
+		t.Error("Preflight requests should not reach next handler")
+	}
+
+	req := httptest.NewRequest("OPTIONS", "/", nil)
+	NewHeader().Origin().SetValue("https://example.com").Write(req.Header)
+	NewHeader().AccessControlRequestMethod().SetValue("PUT").Write(req.Header)
+	resp := httptest.NewRecorder()
+	handler.ServeHTTP(resp, req)
+
+	if v := resp.Header().Get("Access-Control-Allow-Origin"); v != "*" {
+		t.Errorf("Unexpected allowed origin: %s", v)
+	}
+	if v := resp.Header().Get("Access-Control-Allow-Methods"); v != "GET, PUT" {
+		t.Errorf("Unexpected allowed methods: %s", v)
+	}
+	if v := resp.Header().Get("Access-Control-Max-Age"); v != "600" {
+		t.Errorf("Unexpected max age: %s", v)
+	}
+}
+
+func TestCORSWildcardCredentials(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("Any origin together with credentials should panic")
+		}
+	}()
+
+	CORS{
+		AllowedOrigins:   []string{"*"},
+		AllowCredentials: true,
+	}.CORSHandler(http.NotFoundHandler())
+}
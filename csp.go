@@ -0,0 +1,167 @@
+/*
+ * Copyright 2016 Fabrício Godoy
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package web
+
+import (
+	"bytes"
+)
+
+const (
+	// CSPNone defines a source that matches nothing.
+	CSPNone = "'none'"
+	// CSPSelf defines a source that matches current origin.
+	CSPSelf = "'self'"
+	// CSPStrictDynamic defines a source that trusts scripts loaded by already
+	// trusted scripts.
+	CSPStrictDynamic = "'strict-dynamic'"
+	// CSPUnsafeInline defines a source that allows inline resources.
+	CSPUnsafeInline = "'unsafe-inline'"
+	// CSPNonce defines a source that matches resources with the per-request
+	// nonce, which is created by SecurityHeaders and available by CSPNonceFrom.
+	CSPNonce = "'nonce-" + cspNoncePlaceholder + "'"
+
+	cspNoncePlaceholder = "{nonce}"
+)
+
+// A CSPBuilder provides methods to construct a Content Security Policy.
+type CSPBuilder interface {
+	// Build creates and returns the policy as a HTTP header value.
+	Build() string
+
+	// BaseURI sets sources allowed to be used as document base URL.
+	BaseURI(...string) CSPBuilder
+
+	// ConnectSrc sets sources allowed to be loaded by script interfaces.
+	ConnectSrc(...string) CSPBuilder
+
+	// DefaultSrc sets fallback sources for other fetch directives.
+	DefaultSrc(...string) CSPBuilder
+
+	// Directive sets sources to a custom directive.
+	Directive(name string, sources ...string) CSPBuilder
+
+	// FontSrc sets sources allowed to load fonts.
+	FontSrc(...string) CSPBuilder
+
+	// FormAction sets sources allowed to be used as form targets.
+	FormAction(...string) CSPBuilder
+
+	// FrameAncestors sets sources allowed to embed current resource.
+	FrameAncestors(...string) CSPBuilder
+
+	// ImgSrc sets sources allowed to load images.
+	ImgSrc(...string) CSPBuilder
+
+	// ObjectSrc sets sources allowed to load plugins.
+	ObjectSrc(...string) CSPBuilder
+
+	// ScriptSrc sets sources allowed to load scripts.
+	ScriptSrc(...string) CSPBuilder
+
+	// StyleSrc sets sources allowed to load stylesheets.
+	StyleSrc(...string) CSPBuilder
+
+	// UpgradeInsecureRequests sets client to upgrade HTTP requests to HTTPS.
+	UpgradeInsecureRequests() CSPBuilder
+}
+
+type cspDirective struct {
+	name    string
+	sources []string
+}
+
+type cspBuilder struct {
+	directives []cspDirective
+}
+
+// NewCSP creates a new instance of CSPBuilder.
+func NewCSP() CSPBuilder {
+	return &cspBuilder{}
+}
+
+func (b *cspBuilder) Build() string {
+	var buf bytes.Buffer
+	for i, v := range b.directives {
+		if i > 0 {
+			buf.WriteString("; ")
+		}
+		buf.WriteString(v.name)
+		for _, src := range v.sources {
+			buf.WriteByte(' ')
+			buf.WriteString(src)
+		}
+	}
+	return buf.String()
+}
+
+func (b *cspBuilder) BaseURI(sources ...string) CSPBuilder {
+	return b.Directive("base-uri", sources...)
+}
+
+func (b *cspBuilder) ConnectSrc(sources ...string) CSPBuilder {
+	return b.Directive("connect-src", sources...)
+}
+
+func (b *cspBuilder) DefaultSrc(sources ...string) CSPBuilder {
+	return b.Directive("default-src", sources...)
+}
+
+func (b *cspBuilder) Directive(name string, sources ...string) CSPBuilder {
+	for i := range b.directives {
+		if b.directives[i].name == name {
+			b.directives[i].sources = sources
+			return b
+		}
+	}
+
+	b.directives = append(b.directives, cspDirective{name, sources})
+	return b
+}
+
+func (b *cspBuilder) FontSrc(sources ...string) CSPBuilder {
+	return b.Directive("font-src", sources...)
+}
+
+func (b *cspBuilder) FormAction(sources ...string) CSPBuilder {
+	return b.Directive("form-action", sources...)
+}
+
+func (b *cspBuilder) FrameAncestors(sources ...string) CSPBuilder {
+	return b.Directive("frame-ancestors", sources...)
+}
+
+func (b *cspBuilder) ImgSrc(sources ...string) CSPBuilder {
+	return b.Directive("img-src", sources...)
+}
+
+func (b *cspBuilder) ObjectSrc(sources ...string) CSPBuilder {
+	return b.Directive("object-src", sources...)
+}
+
+func (b *cspBuilder) ScriptSrc(sources ...string) CSPBuilder {
+	return b.Directive("script-src", sources...)
+}
+
+func (b *cspBuilder) StyleSrc(sources ...string) CSPBuilder {
+	return b.Directive("style-src", sources...)
+}
+
+func (b *cspBuilder) UpgradeInsecureRequests() CSPBuilder {
+	return b.Directive("upgrade-insecure-requests")
+}
+
+var _ CSPBuilder = (*cspBuilder)(nil)
@@ -21,6 +21,8 @@ Chain
 
 A Chain provides a function to chain HTTP handlers, also know as middlewares,
 before a specified HTTP handler. A Chain is basically a slice of middlewares.
+Middlewares can be named to allow a chain to be described, and a chain can run
+in debug mode recording timings of each middleware.
 
 Header
 
@@ -35,12 +37,24 @@ JSON
 
 Provides a JSONRead and JSONWrite functions for easiest JSON communication, and
 a JSONError struct which defines a format for JSON errors as defined by best
-practices.
+practices. A JSONError can be converted to ProblemDetails, as defined by
+RFC 7807, and ErrorWriter defines how errors raised by this package are
+written. A JSONStreamer streams large content as a JSON array or
+newline-delimited JSON, and a NDJSONReader decodes newline-delimited JSON
+content one record at a time.
+
+Negotiate
+
+Provides a Negotiate function to select the content type which best matches the
+Accept header sent by client, and a Write function that serializes content to
+JSON, XML or plain text accordingly.
 
 SessionStore
 
 A SessionStore provides session tokens to uniquely identify an user session and
 links it to specified data. Each token expires automatically if it is not used
-after defined time.
+after defined time. A SessionCodec can be defined to serialize session data,
+which is required by stores that keep data out of process; JSON, gob and
+MessagePack codecs are provided.
 */
 package web
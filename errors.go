@@ -22,6 +22,24 @@ import (
 	"fmt"
 )
 
+// A HeaderValueError represents an error when a HTTP header value could not be
+// parsed as expected type.
+type HeaderValueError struct {
+	// HTTP header field name.
+	Name string
+	// HTTP header field value.
+	Value string
+	// Name of expected type.
+	Type string
+}
+
+// Error returns string representation of current instance error.
+func (e *HeaderValueError) Error() string {
+	return fmt.Sprintf(
+		"The value '%s' of header '%s' is not a valid %s",
+		e.Value, e.Name, e.Type)
+}
+
 // A InvalidTokenError represents an error when an invalid or expired token is
 // requested.
 type InvalidTokenError string
@@ -31,3 +49,56 @@ func (e InvalidTokenError) Error() string {
 	return fmt.Sprintf(
 		"The requested token '%s' is invalid or is expired", string(e))
 }
+
+// A NDJSONLineError represents an error when a line of newline-delimited JSON
+// content could not be decoded as a record.
+type NDJSONLineError struct {
+	// Line number, starting at one.
+	Line int
+	// The JSONError that should be reported to client.
+	Err *JSONError
+}
+
+// Error returns string representation of current instance error.
+func (e *NDJSONLineError) Error() string {
+	return fmt.Sprintf("The line %d could not be decoded: %s",
+		e.Line, e.Err.Error())
+}
+
+// A SessionDecodeError represents an error when stored session data could not
+// be decoded.
+type SessionDecodeError struct {
+	// The requested token.
+	Token string
+	// Version of stored data.
+	Version int
+	// The underlying error.
+	Err error
+}
+
+// Error returns string representation of current instance error.
+func (e *SessionDecodeError) Error() string {
+	return fmt.Sprintf(
+		"The data of session '%s' (version %d) could not be decoded: %v",
+		e.Token, e.Version, e.Err)
+}
+
+// A UnknownCodecError represents an error when session data was encoded by an
+// unknown codec.
+type UnknownCodecError string
+
+// Error returns string representation of current instance error.
+func (e UnknownCodecError) Error() string {
+	return fmt.Sprintf(
+		"The session codec '%s' does not match current codec", string(e))
+}
+
+// A UnsupportedVersionError represents an error when session data was encoded
+// by a version which has no defined migration.
+type UnsupportedVersionError int
+
+// Error returns string representation of current instance error.
+func (e UnsupportedVersionError) Error() string {
+	return fmt.Sprintf(
+		"The session version %d has no defined migration", int(e))
+}
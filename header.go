@@ -18,8 +18,29 @@ package web
 
 import (
 	"net/http"
+	"strconv"
+	"strings"
+	"time"
 )
 
+const (
+	// HeaderSet replaces any existing values of HTTP header.
+	HeaderSet WriteMode = iota
+	// HeaderAdd adds a new value to HTTP header, keeping existing ones.
+	HeaderAdd
+	// HeaderAddIfAbsent sets HTTP header only when it has no value.
+	HeaderAddIfAbsent
+	// HeaderAppend merges elements of comma-separated list into existing
+	// values of HTTP header, ignoring duplicated elements. Set-Cookie and
+	// authentication challenge headers cannot be merged, and are added as
+	// HeaderAdd does.
+	HeaderAppend
+)
+
+// A WriteMode represents how a Header is written when HTTP header already has
+// values.
+type WriteMode int
+
 // A Header represents a key-value pair in a HTTP header.
 type Header struct {
 	// HTTP header field name.
@@ -33,13 +54,173 @@ func (s Header) Clone() *Header {
 	return &s
 }
 
+// MediaType parses header value of current instance as a media type.
+//
+// Errors:
+// HeaderValueError when value is not a valid media type.
+func (s *Header) MediaType() (MediaType, error) {
+	return parseMediaType(s.Name, s.Value)
+}
+
 // Read gets HTTP header value, as defined by current instance, from Request
 // Header and sets to current instance.
+//
+// Only first value is read when HTTP header has multiple values.
 func (s *Header) Read(h http.Header) *Header {
 	s.Value = h.Get(s.Name)
 	return s
 }
 
+// ReadAll gets all HTTP header values, as defined by current instance, from
+// Request Header and sets to current instance as a comma-separated list.
+func (s *Header) ReadAll(h http.Header) *Header {
+	s.Value = strings.Join(s.Values(h), ", ")
+	return s
+}
+
+// Add adds HTTP header, as defined by current instance, to ResponseWriter
+// Header keeping existing values.
+func (s *Header) Add(h http.Header) *Header {
+	h.Add(s.Name, s.Value)
+	return s
+}
+
+// Append merges elements of header value, as a comma-separated list, into
+// ResponseWriter Header. Elements already defined are not duplicated.
+//
+// Set-Cookie and authentication challenge headers, whose values cannot be
+// merged as lists, are added as Add does.
+func (s *Header) Append(h http.Header) *Header {
+	key := http.CanonicalHeaderKey(s.Name)
+	if unmergeableHeaders[key] {
+		return s.Add(h)
+	}
+
+	elements := (&Header{s.Name, strings.Join(h[key], ",")}).List()
+	for _, v := range s.List() {
+		if !containsElement(elements, v) {
+			elements = append(elements, v)
+		}
+	}
+
+	h[key] = []string{strings.Join(elements, ", ")}
+	return s
+}
+
+// Bool parses header value of current instance as a boolean.
+//
+// Errors:
+// HeaderValueError when value is not "true" or "false".
+func (s *Header) Bool() (bool, error) {
+	switch strings.ToLower(strings.TrimSpace(s.Value)) {
+	case "true":
+		return true, nil
+	case "false":
+		return false, nil
+	}
+	return false, &HeaderValueError{s.Name, s.Value, "boolean"}
+}
+
+// Duration parses header value of current instance as a number of seconds.
+//
+// Errors:
+// HeaderValueError when value is not a non-negative integer.
+func (s *Header) Duration() (time.Duration, error) {
+	secs, err := strconv.ParseInt(strings.TrimSpace(s.Value), 10, 64)
+	if err != nil || secs < 0 {
+		return 0, &HeaderValueError{s.Name, s.Value, "seconds"}
+	}
+	return time.Duration(secs) * time.Second, nil
+}
+
+// Int parses header value of current instance as an integer.
+//
+// Errors:
+// HeaderValueError when value is not an integer.
+func (s *Header) Int() (int64, error) {
+	v, err := strconv.ParseInt(strings.TrimSpace(s.Value), 10, 64)
+	if err != nil {
+		return 0, &HeaderValueError{s.Name, s.Value, "integer"}
+	}
+	return v, nil
+}
+
+// List parses header value of current instance as a comma-separated list.
+// Commas inside quoted strings or URI references enclosed by angle brackets,
+// as used by Link header, do not split elements. Empty elements are ignored.
+func (s *Header) List() []string {
+	result := make([]string, 0)
+	start, quoted, escaped, angled := 0, false, false, false
+	for i := 0; i <= len(s.Value); i++ {
+		if i < len(s.Value) {
+			c := s.Value[i]
+			switch {
+			case escaped:
+				escaped = false
+				continue
+			case angled:
+				angled = c != '>'
+				continue
+			case !quoted && c == '<':
+				angled = true
+				continue
+			case quoted && c == '\\':
+				escaped = true
+				continue
+			case c == '"':
+				quoted = !quoted
+				continue
+			case c != ',' || quoted:
+				continue
+			}
+		}
+
+		if v := strings.TrimSpace(s.Value[start:i]); len(v) > 0 {
+			result = append(result, v)
+		}
+		start = i + 1
+	}
+	return result
+}
+
+// Time parses header value of current instance as a HTTP-date.
+//
+// Errors:
+// HeaderValueError when value is not a valid HTTP-date.
+func (s *Header) Time() (time.Time, error) {
+	t, err := http.ParseTime(strings.TrimSpace(s.Value))
+	if err != nil {
+		return time.Time{}, &HeaderValueError{s.Name, s.Value, "HTTP-date"}
+	}
+	return t, nil
+}
+
+// SetBool sets header value of current instance to specified boolean.
+func (s *Header) SetBool(value bool) *Header {
+	s.Value = strconv.FormatBool(value)
+	return s
+}
+
+// SetDuration sets header value of current instance to specified duration as
+// whole seconds.
+func (s *Header) SetDuration(value time.Duration) *Header {
+	s.Value = strconv.FormatInt(int64(value/time.Second), 10)
+	return s
+}
+
+// SetInt sets header value of current instance to specified integer.
+func (s *Header) SetInt(value int64) *Header {
+	s.Value = strconv.FormatInt(value, 10)
+	return s
+}
+
+// SetList sets header value of current instance to specified values as a
+// comma-separated list.
+func (s *Header) SetList(values ...string) *Header {
+	s.Value = strings.Join(values, ", ")
+	return s
+}
+
 // SetName sets header name of current instance.
 func (s *Header) SetName(name string) *Header {
 	s.Name = name
@@ -52,9 +233,80 @@ func (s *Header) SetValue(value string) *Header {
 	return s
 }
 
+// SetTime sets header value of current instance to specified time as a
+// HTTP-date.
+func (s *Header) SetTime(value time.Time) *Header {
+	s.Value = value.UTC().Format(http.TimeFormat)
+	return s
+}
+
+// Values gets all HTTP header values, as defined by current instance, from
+// specified Header.
+func (s *Header) Values(h http.Header) []string {
+	return h[http.CanonicalHeaderKey(s.Name)]
+}
+
 // Write sets HTTP header, as defined by current instance, to ResponseWriter
 // Header.
 func (s *Header) Write(h http.Header) *Header {
 	h.Set(s.Name, s.Value)
 	return s
 }
+
+// WriteWith writes HTTP header, as defined by current instance, to
+// ResponseWriter Header using specified mode.
+func (s *Header) WriteWith(h http.Header, mode WriteMode) *Header {
+	switch mode {
+	case HeaderAdd:
+		return s.Add(h)
+	case HeaderAddIfAbsent:
+		if len(s.Values(h)) == 0 {
+			return s.Write(h)
+		}
+		return s
+	case HeaderAppend:
+		return s.Append(h)
+	default:
+		return s.Write(h)
+	}
+}
+
+// unmergeableHeaders defines HTTP headers whose values cannot be merged as
+// comma-separated lists.
+var unmergeableHeaders = map[string]bool{
+	"Set-Cookie":         true,
+	"Www-Authenticate":   true,
+	"Proxy-Authenticate": true,
+}
+
+// containsElement returns whether list contains specified element. Elements
+// having quoted strings are compared case-sensitively.
+func containsElement(list []string, value string) bool {
+	if !strings.Contains(value, "\"") {
+		return containsFold(list, value)
+	}
+	for _, v := range list {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func containsFold(list []string, value string) bool {
+	for _, v := range list {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+	return false
+}
+
+func containsAllFold(list []string, values []string) bool {
+	for _, v := range values {
+		if !containsFold(list, v) {
+			return false
+		}
+	}
+	return true
+}
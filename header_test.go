@@ -19,6 +19,7 @@ package web
 import (
 	"net/http"
 	"testing"
+	"time"
 )
 
 func TestHeader(t *testing.T) {
@@ -105,3 +106,172 @@ func TestHeader(t *testing.T) {
 		t.Errorf("Headers value modified: %s", val[0])
 	}
 }
+
+func TestHeaderTypedValues(t *testing.T) {
+	h := NewHeader().AccessControlMaxAge().SetDuration(90 * time.Second)
+	if h.Value != "90" {
+		t.Errorf("Unexpected duration value: %s", h.Value)
+	}
+	if d, err := h.Duration(); err != nil || d != 90*time.Second {
+		t.Errorf("Unexpected duration parsed: %v (%v)", d, err)
+	}
+
+	h = NewHeader().AccessControlAllowMethods().SetList("GET", "POST")
+	if h.Value != "GET, POST" {
+		t.Errorf("Unexpected list value: %s", h.Value)
+	}
+	h.SetValue(" GET,,POST , PUT")
+	if l := h.List(); len(l) != 3 || l[0] != "GET" || l[2] != "PUT" {
+		t.Errorf("Unexpected list parsed: %v", l)
+	}
+
+	h = NewHeader().AccessControlAllowCredentials().SetBool(true)
+	if h.Value != "true" {
+		t.Errorf("Unexpected boolean value: %s", h.Value)
+	}
+	if b, err := h.Bool(); err != nil || !b {
+		t.Errorf("Unexpected boolean parsed: %v (%v)", b, err)
+	}
+
+	date := time.Date(2016, 3, 1, 10, 20, 30, 0, time.FixedZone("BRT", -10800))
+	h = NewHeader().Empty().SetName("Last-Modified").SetTime(date)
+	if h.Value != "Tue, 01 Mar 2016 13:20:30 GMT" {
+		t.Errorf("Unexpected time value: %s", h.Value)
+	}
+	if d, err := h.Time(); err != nil || !d.Equal(date) {
+		t.Errorf("Unexpected time parsed: %v (%v)", d, err)
+	}
+
+	h = NewHeader().Empty().SetName("Content-Length").SetInt(1024)
+	if v, err := h.Int(); err != nil || v != 1024 {
+		t.Errorf("Unexpected integer parsed: %v (%v)", v, err)
+	}
+}
+
+func TestHeaderMalformedValues(t *testing.T) {
+	h := NewHeader().Empty().SetName("Testing-Name").SetValue("foo")
+
+	if _, err := h.Bool(); err == nil {
+		t.Error("Malformed boolean should return an error")
+	}
+	if _, err := h.Duration(); err == nil {
+		t.Error("Malformed duration should return an error")
+	}
+	if _, err := h.SetValue("-1").Duration(); err == nil {
+		t.Error("Negative duration should return an error")
+	}
+	if _, err := h.Int(); err != nil {
+		t.Errorf("Negative integer should be parsed: %v", err)
+	}
+	_, err := h.SetValue("yesterday").Time()
+	if verr, ok := err.(*HeaderValueError); !ok || verr.Name != "Testing-Name" {
+		t.Errorf("Expected HeaderValueError but got %#v", err)
+	}
+}
+
+func TestHeaderMultipleValues(t *testing.T) {
+	h := make(http.Header)
+	NewHeader().Vary().SetValue("Accept-Encoding").Write(h)
+	NewHeader().Vary().SetList("Origin", "accept-encoding").Append(h)
+	NewHeader().Vary().SetValue("Origin").Append(h)
+
+	if v := NewHeader().Vary().Values(h); len(v) != 1 ||
+		v[0] != "Accept-Encoding, Origin" {
+		t.Errorf("Unexpected merged values: %v", v)
+	}
+
+	link := NewHeader().Empty().SetName("Link")
+	link.SetValue("</a>; rel=next").Add(h)
+	link.SetValue("</b>; rel=prev").Add(h)
+	if v := link.Values(h); len(v) != 2 {
+		t.Errorf("Unexpected added values: %v", v)
+	}
+	if v := link.ReadAll(h).Value; v != "</a>; rel=next, </b>; rel=prev" {
+		t.Errorf("Unexpected values read: %s", v)
+	}
+	if v := link.Read(h).Value; v != "</a>; rel=next" {
+		t.Errorf("Unexpected first value read: %s", v)
+	}
+
+	h = make(http.Header)
+	NewHeader().Empty().
+		SetName("Link").
+		SetValue(`<https://x/?a=1,2>; rel="next"`).
+		Append(h)
+	NewHeader().Empty().
+		SetName("Link").
+		SetValue(`<https://x/?a=1,2>; rel="next", </b,c>; rel=prev`).
+		Append(h)
+	if v := link.Values(h); len(v) != 1 ||
+		v[0] != `<https://x/?a=1,2>; rel="next", </b,c>; rel=prev` {
+		t.Errorf("Unexpected merged links: %q", v)
+	}
+
+	testValues := []struct {
+		mode     WriteMode
+		expected []string
+	}{
+		{HeaderSet, []string{"b"}},
+		{HeaderAdd, []string{"a", "b"}},
+		{HeaderAddIfAbsent, []string{"a"}},
+		{HeaderAppend, []string{"a, b"}},
+	}
+	for _, v := range testValues {
+		h := make(http.Header)
+		NewHeader().Empty().SetName("Cache-Control").SetValue("a").Write(h)
+		values := NewHeader().Empty().
+			SetName("cache-control").
+			SetValue("b").
+			WriteWith(h, v.mode).
+			Values(h)
+		if !equalStrings(values, v.expected) {
+			t.Errorf("Unexpected values for mode %d: %v", v.mode, values)
+		}
+	}
+
+	h = make(http.Header)
+	NewHeader().Empty().
+		SetName("Cache-Control").
+		SetValue("a").
+		WriteWith(h, HeaderAddIfAbsent)
+	if v := h.Get("Cache-Control"); v != "a" {
+		t.Errorf("Absent header should be written: %s", v)
+	}
+}
+
+func TestHeaderQuotedList(t *testing.T) {
+	l := NewHeader().CacheControl().
+		SetValue(`no-cache="Set-Cookie, X-A", private, x="a\", b"`).
+		List()
+	expected := []string{`no-cache="Set-Cookie, X-A"`, "private", `x="a\", b"`}
+	if !equalStrings(l, expected) {
+		t.Errorf("Unexpected list elements: %q", l)
+	}
+
+	h := make(http.Header)
+	NewHeader().CacheControl().
+		SetValue(`no-cache="Set-Cookie, X-A"`).
+		Write(h)
+	NewHeader().CacheControl().
+		SetValue(`no-cache="Set-Cookie", no-cache="Set-Cookie, X-A", private`).
+		Append(h)
+	if v := h.Get("Cache-Control"); v !=
+		`no-cache="Set-Cookie, X-A", no-cache="Set-Cookie", private` {
+		t.Errorf("Unexpected merged value: %s", v)
+	}
+
+	h = make(http.Header)
+	challenges := []string{
+		`Digest realm="a", qop="auth"`,
+		`Digest realm="b", qop="auth"`,
+	}
+	for _, v := range challenges {
+		NewHeader().Empty().
+			SetName("WWW-Authenticate").
+			SetValue(v).
+			WriteWith(h, HeaderAppend)
+	}
+	if v := h["Www-Authenticate"]; !equalStrings(v, challenges) {
+		t.Errorf("Challenges should be added unmerged: %q", v)
+	}
+}
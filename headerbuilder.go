@@ -17,7 +17,9 @@
 package web
 
 const (
+	headerNameAccept      = "Accept"
 	headerNameContentType = "Content-Type"
+	headerNameRequestID   = "X-Request-ID"
 )
 
 // A HeaderBuilder provides pre-defined HTTP headers.
@@ -28,6 +30,15 @@ func NewHeader() HeaderBuilder {
 	return HeaderBuilder(0)
 }
 
+// Accept creates a HTTP header to client indicate which content types are
+// acceptable.
+func (HeaderBuilder) Accept() *Header {
+	return &Header{
+		headerNameAccept,
+		"", // comma-separated list of media ranges with optional quality
+	}
+}
+
 // AccessControlAllowCredentials creates a HTTP header to CORS-able API indicate
 // that authentication is allowed.
 func (HeaderBuilder) AccessControlAllowCredentials() *Header {
@@ -64,6 +75,15 @@ func (HeaderBuilder) AccessControlAllowOrigin() *Header {
 	}
 }
 
+// AccessControlExposeHeaders creates a HTTP header to CORS-able API indicate
+// which HTTP headers can be read by client.
+func (HeaderBuilder) AccessControlExposeHeaders() *Header {
+	return &Header{
+		"Access-Control-Expose-Headers",
+		"", // comma-separated list of HTTP headers
+	}
+}
+
 // AccessControlMaxAge creates a HTTP header to CORS-able API indicate how long
 // preflight results should be cached.
 func (HeaderBuilder) AccessControlMaxAge() *Header {
@@ -91,6 +111,33 @@ func (HeaderBuilder) AccessControlRequestMethod() *Header {
 	}
 }
 
+// CacheControl creates a HTTP header to define caching directives, as built by
+// CacheControlBuilder.
+func (HeaderBuilder) CacheControl() *Header {
+	return &Header{
+		"Cache-Control",
+		"", // comma-separated list of directives
+	}
+}
+
+// ContentLength creates a HTTP header to define the length of content, in
+// bytes.
+func (HeaderBuilder) ContentLength() *Header {
+	return &Header{
+		"Content-Length",
+		"", // decimal number of bytes
+	}
+}
+
+// ContentSecurityPolicy creates a HTTP header to define which resources the
+// client is allowed to load.
+func (HeaderBuilder) ContentSecurityPolicy() *Header {
+	return &Header{
+		"Content-Security-Policy",
+		"", // semicolon-separated list of directives
+	}
+}
+
 // ContentType creates a HTTP header builder to define a content type.
 func (HeaderBuilder) ContentType() HeaderContentTypeBuilder {
 	return HeaderContentTypeBuilder(0)
@@ -104,6 +151,59 @@ func (HeaderBuilder) Empty() *Header {
 	}
 }
 
+// ETag creates a HTTP header to identify a specific version of a resource.
+func (HeaderBuilder) ETag() *Header {
+	return &Header{
+		"ETag",
+		"", // quoted entity tag with optional weak prefix
+	}
+}
+
+// IfMatch creates a HTTP header to client request a change only whether
+// current version of resource matches any of specified entity tags.
+func (HeaderBuilder) IfMatch() *Header {
+	return &Header{
+		"If-Match",
+		"", // comma-separated list of entity tags or asterisk
+	}
+}
+
+// IfModifiedSince creates a HTTP header to client request a resource only
+// whether it was modified since specified time.
+func (HeaderBuilder) IfModifiedSince() *Header {
+	return &Header{
+		"If-Modified-Since",
+		"", // HTTP-date
+	}
+}
+
+// IfNoneMatch creates a HTTP header to client request a resource only whether
+// its current version does not match any of specified entity tags.
+func (HeaderBuilder) IfNoneMatch() *Header {
+	return &Header{
+		"If-None-Match",
+		"", // comma-separated list of entity tags or asterisk
+	}
+}
+
+// IfUnmodifiedSince creates a HTTP header to client request a change only
+// whether resource was not modified since specified time.
+func (HeaderBuilder) IfUnmodifiedSince() *Header {
+	return &Header{
+		"If-Unmodified-Since",
+		"", // HTTP-date
+	}
+}
+
+// LastModified creates a HTTP header to define when a resource was last
+// modified.
+func (HeaderBuilder) LastModified() *Header {
+	return &Header{
+		"Last-Modified",
+		"", // HTTP-date
+	}
+}
+
 // Location creates a HTTP header to define location of new object.
 func (HeaderBuilder) Location() *Header {
 	return &Header{
@@ -120,6 +220,68 @@ func (HeaderBuilder) Origin() *Header {
 	}
 }
 
+// PermissionsPolicy creates a HTTP header to define which browser features
+// can be used.
+func (HeaderBuilder) PermissionsPolicy() *Header {
+	return &Header{
+		"Permissions-Policy",
+		"", // comma-separated list of feature allowlists
+	}
+}
+
+// ReferrerPolicy creates a HTTP header to define how much referrer
+// information should be sent by client.
+func (HeaderBuilder) ReferrerPolicy() *Header {
+	return &Header{
+		"Referrer-Policy",
+		"", // policy name
+	}
+}
+
+// RequestID creates a HTTP header to identify a request across services.
+func (HeaderBuilder) RequestID() *Header {
+	return &Header{
+		headerNameRequestID,
+		"", // opaque identifier
+	}
+}
+
+// StrictTransportSecurity creates a HTTP header to require client to access
+// current host only using HTTPS.
+func (HeaderBuilder) StrictTransportSecurity() *Header {
+	return &Header{
+		"Strict-Transport-Security",
+		"", // max-age in seconds and optional directives
+	}
+}
+
+// Vary creates a HTTP header to indicate which request headers was used to
+// select the response.
+func (HeaderBuilder) Vary() *Header {
+	return &Header{
+		"Vary",
+		"", // comma-separated list of HTTP headers
+	}
+}
+
+// XContentTypeOptions creates a HTTP header to prevent client from sniffing
+// content type.
+func (HeaderBuilder) XContentTypeOptions() *Header {
+	return &Header{
+		"X-Content-Type-Options",
+		"", // nosniff
+	}
+}
+
+// XFrameOptions creates a HTTP header to define whether response can be
+// rendered into a frame.
+func (HeaderBuilder) XFrameOptions() *Header {
+	return &Header{
+		"X-Frame-Options",
+		"", // DENY or SAMEORIGIN
+	}
+}
+
 // A HeaderContentTypeBuilder provides pre-defined Content Types HTTP headers.
 type HeaderContentTypeBuilder int
 
@@ -147,6 +309,23 @@ func (HeaderContentTypeBuilder) JSON() *Header {
 	}
 }
 
+// NDJSON creates a HTTP header to define newline-delimited JSON content type.
+func (HeaderContentTypeBuilder) NDJSON() *Header {
+	return &Header{
+		headerNameContentType,
+		"application/x-ndjson; charset=utf-8",
+	}
+}
+
+// ProblemJSON creates a HTTP header to define Problem Details JSON content
+// type, as defined by RFC 7807.
+func (HeaderContentTypeBuilder) ProblemJSON() *Header {
+	return &Header{
+		headerNameContentType,
+		"application/problem+json; charset=utf-8",
+	}
+}
+
 // Text creates a HTTP header to define plain text content type.
 func (HeaderContentTypeBuilder) Text() *Header {
 	return &Header{
@@ -17,9 +17,13 @@
 package web
 
 import (
+	"bytes"
 	"encoding/json"
+	"fmt"
 	"io"
 	"net/http"
+
+	fflib "github.com/pquerna/ffjson/fflib/v1"
 )
 
 const (
@@ -29,8 +33,13 @@ const (
 
 // JSONWrite sets response content type to JSON, sets HTTP status and serializes
 // defined content to JSON format.
+//
+// A JSONError or ProblemDetails without request identifier gets the one
+// defined by response headers, when available. A ProblemDetails is sent as
+// Problem Details JSON content type.
 func JSONWrite(w http.ResponseWriter, status int, content interface{}) error {
-	NewHeader().ContentType().JSON().Write(w.Header())
+	content, contentType := prepareJSONContent(w, content)
+	contentType.Write(w.Header())
 	w.WriteHeader(status)
 	if content != nil {
 		err := json.NewEncoder(w).Encode(content)
@@ -42,37 +51,164 @@ func JSONWrite(w http.ResponseWriter, status int, content interface{}) error {
 	return nil
 }
 
+// JSONErrorWrite writes specified error as JSON, using HTTP status defined by
+// error.
+func JSONErrorWrite(w http.ResponseWriter, e *JSONError) error {
+	return JSONWrite(w, e.Status, e)
+}
+
+// JSONWriteBuffered serializes defined content to JSON format before anything
+// is written, then sets response content type to JSON, content length and
+// HTTP status, as JSONWrite does.
+//
+// An Internal Server Error (500) JSONError is written instead when content
+// cannot be serialized, as defined by ErrorWriter, and the serialization error
+// is returned.
+func JSONWriteBuffered(
+	w http.ResponseWriter,
+	status int,
+	content interface{},
+) error {
+	content, contentType := prepareJSONContent(w, content)
+	var data []byte
+	var err error
+	if content != nil {
+		data, err = marshalJSON(content)
+	}
+	if err != nil {
+		writeError(w, NewJSONError().FromError(err).Build())
+		return err
+	}
+
+	contentType.Write(w.Header())
+	NewHeader().ContentLength().SetInt(int64(len(data))).Write(w.Header())
+	w.WriteHeader(status)
+	_, err = w.Write(data)
+	return err
+}
+
+// prepareJSONContent returns content to be serialized and content type header
+// to be written by JSON writers.
+func prepareJSONContent(
+	w http.ResponseWriter,
+	content interface{},
+) (interface{}, *Header) {
+	id := w.Header().Get(headerNameRequestID)
+	contentType := NewHeader().ContentType().JSON()
+	switch v := content.(type) {
+	case *JSONError:
+		if v != nil && len(v.RequestID) == 0 && len(id) > 0 {
+			withID := *v
+			withID.RequestID = id
+			content = &withID
+		}
+	case ProblemDetails:
+		return prepareJSONContent(w, &v)
+	case *ProblemDetails:
+		contentType = NewHeader().ContentType().ProblemJSON()
+		if v == nil || len(id) == 0 {
+			break
+		}
+		if _, ok := v.Extensions[problemExtRequestID]; !ok {
+			withID := *v
+			withID.Extensions = make(map[string]interface{}, len(v.Extensions)+1)
+			for k, ext := range v.Extensions {
+				withID.Extensions[k] = ext
+			}
+			withID.Extensions[problemExtRequestID] = id
+			content = &withID
+		}
+	}
+
+	return content, contentType
+}
+
+// A ffjsonMarshaler represents a type whose JSON serializer was generated by
+// ffjson.
+type ffjsonMarshaler interface {
+	MarshalJSONBuf(buf fflib.EncodingBuffer) error
+}
+
+// marshalJSON serializes specified value to JSON format, followed by a newline
+// as json.Encoder does. Types generated by ffjson are serialized directly to
+// buffer.
+func marshalJSON(v interface{}) ([]byte, error) {
+	if m, ok := v.(ffjsonMarshaler); ok {
+		var buf fflib.Buffer
+		if err := m.MarshalJSONBuf(&buf); err != nil {
+			return nil, err
+		}
+		buf.WriteByte('\n')
+		return buf.Bytes(), nil
+	}
+
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(v); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
 // JSONRead tries to read client sent content using JSON decoding and
-// writes it to object pointed to by obj.
+// writes it to object pointed to by obj. A Request Entity Too Large (413)
+// JSONError is written when content is larger than maxlen.
 //
 // Returns true whether no error occurred; otherwise, false.
 //
-// Body is automatically closed when true is returned.
+// Body is always drained, up to a bound, and closed.
 func JSONRead(
 	body io.ReadCloser,
 	maxlen int64,
 	obj interface{},
 	w http.ResponseWriter,
 ) bool {
-	if err := json.
-		NewDecoder(io.LimitReader(body, maxlen)).
-		Decode(obj); err != nil {
-
-		jerr := NewJSONError().
-			FromError(err).
-			Status(StatusUnprocessableEntity).
-			Build()
-		JSONWrite(w, jerr.Status, jerr)
-		return false
+	return JSONReader{MaxLength: maxlen}.Read(body, obj, w)
+}
+
+// DecodeJSON tries to read request body using JSON decoding and writes it to
+// object pointed to by obj, as JSONReadRequest does, but leaves responding to
+// caller.
+//
+// Returns nil whether no error occurred; otherwise, a *JSONError whose status
+// defines the HTTP status that should be answered.
+func DecodeJSON(r *http.Request, maxlen int64, obj interface{}) error {
+	return JSONReader{MaxLength: maxlen}.Decode(r, obj)
+}
+
+// JSONReadRequest tries to read request body using JSON decoding and writes it
+// to object pointed to by obj, as JSONRead does.
+//
+// An Unsupported Media Type (415) JSONError is written when request defines a
+// content type which is not JSON-compatible, as "application/json" or
+// "application/vnd.foo+json". A Request Entity Too Large (413) JSONError is
+// written, before reading anything, when request defines a content length
+// larger than maxlen.
+func JSONReadRequest(
+	r *http.Request,
+	maxlen int64,
+	obj interface{},
+	w http.ResponseWriter,
+) bool {
+	return JSONReader{MaxLength: maxlen}.ReadRequest(r, obj, w)
+}
+
+// checkJSONContentType returns a JSONError when specified request defines a
+// content type which is not JSON-compatible.
+func checkJSONContentType(r *http.Request) *JSONError {
+	ct := NewHeader().ContentType().Empty().Read(r.Header)
+	if len(ct.Value) == 0 {
+		return nil
 	}
 
-	if err := body.Close(); err != nil {
-		jerr := NewJSONError().
-			FromError(err).
-			Build()
-		JSONWrite(w, jerr.Status, jerr)
-		return false
+	mt, err := ct.MediaType()
+	if err == nil && mt.IsJSON() {
+		return nil
 	}
 
-	return true
+	return NewJSONError().
+		Status(http.StatusUnsupportedMediaType).
+		Message(fmt.Sprintf(
+			"The content type '%s' is not supported, expected JSON",
+			ct.Value)).
+		Build()
 }
@@ -22,6 +22,7 @@ import (
 	"math"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 )
 
@@ -89,6 +90,22 @@ func (ur *UnbufferedResponse) WriteHeader(code int) {
 	ur.wroteHeader = true
 }
 
+type countingReader struct {
+	*strings.Reader
+	reads  int
+	closes int
+}
+
+func (r *countingReader) Read(p []byte) (int, error) {
+	r.reads++
+	return r.Reader.Read(p)
+}
+
+func (r *countingReader) Close() error {
+	r.closes++
+	return nil
+}
+
 func TestJSONRead(t *testing.T) {
 	foo := Foo{
 		40,
@@ -154,3 +171,152 @@ func TestJSONWrite(t *testing.T) {
 				"\n\nOriginal: %#v\nDecoded: %#v", foo, fooCopy)
 	}
 }
+
+func TestDecodeJSON(t *testing.T) {
+	testValues := []struct {
+		contentType string
+		body        string
+		status      int
+	}{
+		{"application/json", `{"Number":40}`, 0},
+		{"text/plain", `{"Number":40}`, http.StatusUnsupportedMediaType},
+		{"application/json", `{"Number":`, StatusUnprocessableEntity},
+		{"application/json", strings.Repeat(" ", 200),
+			http.StatusRequestEntityTooLarge},
+	}
+
+	for _, v := range testValues {
+		req := httptest.NewRequest("POST", "/", strings.NewReader(v.body))
+		NewHeader().ContentType().Empty().
+			SetValue(v.contentType).
+			Write(req.Header)
+
+		var foo Foo
+		err := DecodeJSON(req, 100, &foo)
+		if v.status == 0 {
+			if err != nil {
+				t.Errorf("Unexpected error for '%s': %v", v.body, err)
+			}
+			continue
+		}
+
+		jerr, ok := err.(*JSONError)
+		if !ok {
+			t.Fatalf("Expected JSONError but got %#v", err)
+		}
+		if jerr.Status != v.status {
+			t.Errorf("Unexpected status for '%s': %d", v.body, jerr.Status)
+		}
+	}
+}
+
+func TestJSONReadClosesBody(t *testing.T) {
+	testValues := []struct {
+		body string
+		ok   bool
+	}{
+		{`{"Number":40}`, true},
+		{`{"Number":"forty"}` + strings.Repeat(" ", 1000), false},
+		{`{"Number":`, false},
+		{strings.Repeat(" ", 200), false},
+	}
+
+	for _, v := range testValues {
+		body := &countingReader{Reader: strings.NewReader(v.body)}
+		var foo Foo
+		ok := JSONRead(body, 100, &foo, httptest.NewRecorder())
+
+		if ok != v.ok {
+			t.Errorf("Unexpected result for '%s': %v", v.body, ok)
+		}
+		if body.closes != 1 {
+			t.Errorf("Body should be closed once for '%s': %d closes",
+				v.body, body.closes)
+		}
+		if body.reads == 0 || body.Len() > 0 {
+			t.Errorf("Body should be drained for '%s': %d bytes left",
+				v.body, body.Len())
+		}
+	}
+
+	// Drain is bounded, so huge bodies are not read to their end
+	huge := `{"Number":` + strings.Repeat(" ", 2*maxDrainLength)
+	body := &countingReader{Reader: strings.NewReader(huge)}
+	var foo Foo
+	JSONRead(body, 100, &foo, httptest.NewRecorder())
+	if body.closes != 1 || body.Len() == 0 {
+		t.Errorf("Huge body should be closed without being drained: "+
+			"%d closes, %d bytes left", body.closes, body.Len())
+	}
+	if body.reads > 2*maxDrainLength/512 {
+		t.Errorf("Huge body should be read in few chunks: %d reads",
+			body.reads)
+	}
+}
+
+func TestJSONWriteBuffered(t *testing.T) {
+	foo := Foo{40, math.MaxInt64, 93.09, "Lorem ipsum", true}
+	w := httptest.NewRecorder()
+	if err := JSONWriteBuffered(w, http.StatusCreated, foo); err != nil {
+		t.Fatalf("Error on JSONWriteBuffered: %v", err)
+	}
+
+	if w.Code != http.StatusCreated {
+		t.Errorf("Unexpected status: %d", w.Code)
+	}
+	length, _ := NewHeader().ContentLength().Read(w.Header()).Int()
+	if length != int64(w.Body.Len()) {
+		t.Errorf("Content length %d does not match body length %d",
+			length, w.Body.Len())
+	}
+
+	var fooCopy Foo
+	if err := json.Unmarshal(w.Body.Bytes(), &fooCopy); err != nil {
+		t.Fatalf("Error on decoding object: %v", err)
+	}
+	if !foo.IsEqual(fooCopy) {
+		t.Errorf("Decoded object is not equal to encoded one: %#v", fooCopy)
+	}
+
+	w = httptest.NewRecorder()
+	NewHeader().RequestID().SetValue("abc").Write(w.Header())
+	jerr := NewJSONError().
+		Status(http.StatusNotFound).
+		Message("not found").
+		Build()
+	if err := JSONWriteBuffered(w, jerr.Status, jerr); err != nil {
+		t.Fatalf("Error on JSONWriteBuffered: %v", err)
+	}
+
+	var jerrCopy JSONError
+	if err := json.Unmarshal(w.Body.Bytes(), &jerrCopy); err != nil {
+		t.Fatalf("Error on decoding JSONError: %v", err)
+	}
+	if w.Code != http.StatusNotFound ||
+		jerrCopy.Message != "not found" ||
+		jerrCopy.RequestID != "abc" {
+		t.Errorf("Unexpected JSONError written: %d %#v", w.Code, jerrCopy)
+	}
+}
+
+func TestJSONWriteBufferedError(t *testing.T) {
+	w := httptest.NewRecorder()
+	err := JSONWriteBuffered(w, http.StatusOK, map[string]float64{
+		"value": math.Inf(1),
+	})
+	if err == nil {
+		t.Fatal("Expected encoding error")
+	}
+
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("Unexpected status: %d", w.Code)
+	}
+	var jerr JSONError
+	if err := json.Unmarshal(w.Body.Bytes(), &jerr); err != nil {
+		t.Fatalf("Error on decoding JSONError: %v", err)
+	}
+	if jerr.Status != http.StatusInternalServerError ||
+		jerr.Message != err.Error() {
+		t.Errorf("Unexpected JSONError written: %#v", jerr)
+	}
+}
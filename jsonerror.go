@@ -20,6 +20,7 @@ package web
 
 import (
 	"bytes"
+	"strings"
 )
 
 // A JSONError represents an error returned by JSON-based API.
@@ -34,6 +35,22 @@ type JSONError struct {
 	Message string `json:"message,omitempty"`
 	// A URL for reference.
 	MoreInfo string `json:"moreInfo,omitempty"`
+	// Identifier of the request which caused the error.
+	RequestID string `json:"requestId,omitempty"`
+	// Errors of specific input fields.
+	Fields []FieldError `json:"fields,omitempty"`
+}
+
+// A FieldError represents an error of a specific input field.
+type FieldError struct {
+	// JSON pointer to the invalid field, as defined by RFC 6901.
+	Pointer string `json:"pointer"`
+	// Name of the rule which was violated.
+	Rule string `json:"rule,omitempty"`
+	// A message with error details.
+	Message string `json:"message,omitempty"`
+	// The rejected value.
+	Value interface{} `json:"value,omitempty"`
 }
 
 // Error returns string representation of current instance error.
@@ -57,3 +74,15 @@ func (e *JSONError) Error() string {
 func (e *JSONError) String() string {
 	return e.Error()
 }
+
+// JSONPointer creates a JSON pointer, as defined by RFC 6901, from specified
+// reference tokens.
+func JSONPointer(tokens ...string) string {
+	var buf bytes.Buffer
+	for _, v := range tokens {
+		buf.WriteByte('/')
+		v = strings.Replace(v, "~", "~0", -1)
+		buf.WriteString(strings.Replace(v, "/", "~1", -1))
+	}
+	return buf.String()
+}
@@ -1,30 +1,382 @@
-// DO NOT EDIT!
-// Code generated by ffjson <https://github.com/pquerna/ffjson>
+// Code generated by ffjson <https://github.com/pquerna/ffjson>. DO NOT EDIT.
 // source: jsonerror.go
-// DO NOT EDIT!
 
 package web
 
 import (
 	"bytes"
+	"encoding/json"
 	"fmt"
 	fflib "github.com/pquerna/ffjson/fflib/v1"
 )
 
-func (mj *JSONError) MarshalJSON() ([]byte, error) {
+// MarshalJSON marshal bytes to json - template
+func (j *FieldError) MarshalJSON() ([]byte, error) {
 	var buf fflib.Buffer
-	if mj == nil {
+	if j == nil {
 		buf.WriteString("null")
 		return buf.Bytes(), nil
 	}
-	err := mj.MarshalJSONBuf(&buf)
+	err := j.MarshalJSONBuf(&buf)
 	if err != nil {
 		return nil, err
 	}
 	return buf.Bytes(), nil
 }
-func (mj *JSONError) MarshalJSONBuf(buf fflib.EncodingBuffer) error {
-	if mj == nil {
+
+// MarshalJSONBuf marshal buff to json - template
+func (j *FieldError) MarshalJSONBuf(buf fflib.EncodingBuffer) error {
+	if j == nil {
+		buf.WriteString("null")
+		return nil
+	}
+	var err error
+	var obj []byte
+	_ = obj
+	_ = err
+	buf.WriteString(`{ "pointer":`)
+	fflib.WriteJsonString(buf, string(j.Pointer))
+	buf.WriteByte(',')
+	if len(j.Rule) != 0 {
+		buf.WriteString(`"rule":`)
+		fflib.WriteJsonString(buf, string(j.Rule))
+		buf.WriteByte(',')
+	}
+	if len(j.Message) != 0 {
+		buf.WriteString(`"message":`)
+		fflib.WriteJsonString(buf, string(j.Message))
+		buf.WriteByte(',')
+	}
+	if j.Value != nil {
+		buf.WriteString(`"value":`)
+		/* Interface types must use runtime reflection. type=interface {} kind=interface */
+		err = buf.Encode(j.Value)
+		if err != nil {
+			return err
+		}
+		buf.WriteByte(',')
+	}
+	buf.Rewind(1)
+	buf.WriteByte('}')
+	return nil
+}
+
+const (
+	ffjtFieldErrorbase = iota
+	ffjtFieldErrornosuchkey
+
+	ffjtFieldErrorPointer
+
+	ffjtFieldErrorRule
+
+	ffjtFieldErrorMessage
+
+	ffjtFieldErrorValue
+)
+
+var ffjKeyFieldErrorPointer = []byte("pointer")
+
+var ffjKeyFieldErrorRule = []byte("rule")
+
+var ffjKeyFieldErrorMessage = []byte("message")
+
+var ffjKeyFieldErrorValue = []byte("value")
+
+// UnmarshalJSON umarshall json - template of ffjson
+func (j *FieldError) UnmarshalJSON(input []byte) error {
+	fs := fflib.NewFFLexer(input)
+	return j.UnmarshalJSONFFLexer(fs, fflib.FFParse_map_start)
+}
+
+// UnmarshalJSONFFLexer fast json unmarshall - template ffjson
+func (j *FieldError) UnmarshalJSONFFLexer(fs *fflib.FFLexer, state fflib.FFParseState) error {
+	var err error
+	currentKey := ffjtFieldErrorbase
+	_ = currentKey
+	tok := fflib.FFTok_init
+	wantedTok := fflib.FFTok_init
+
+mainparse:
+	for {
+		tok = fs.Scan()
+		//	println(fmt.Sprintf("debug: tok: %v  state: %v", tok, state))
+		if tok == fflib.FFTok_error {
+			goto tokerror
+		}
+
+		switch state {
+
+		case fflib.FFParse_map_start:
+			if tok != fflib.FFTok_left_bracket {
+				wantedTok = fflib.FFTok_left_bracket
+				goto wrongtokenerror
+			}
+			state = fflib.FFParse_want_key
+			continue
+
+		case fflib.FFParse_after_value:
+			if tok == fflib.FFTok_comma {
+				state = fflib.FFParse_want_key
+			} else if tok == fflib.FFTok_right_bracket {
+				goto done
+			} else {
+				wantedTok = fflib.FFTok_comma
+				goto wrongtokenerror
+			}
+
+		case fflib.FFParse_want_key:
+			// json {} ended. goto exit. woo.
+			if tok == fflib.FFTok_right_bracket {
+				goto done
+			}
+			if tok != fflib.FFTok_string {
+				wantedTok = fflib.FFTok_string
+				goto wrongtokenerror
+			}
+
+			kn := fs.Output.Bytes()
+			if len(kn) <= 0 {
+				// "" case. hrm.
+				currentKey = ffjtFieldErrornosuchkey
+				state = fflib.FFParse_want_colon
+				goto mainparse
+			} else {
+				switch kn[0] {
+
+				case 'm':
+
+					if bytes.Equal(ffjKeyFieldErrorMessage, kn) {
+						currentKey = ffjtFieldErrorMessage
+						state = fflib.FFParse_want_colon
+						goto mainparse
+					}
+
+				case 'p':
+
+					if bytes.Equal(ffjKeyFieldErrorPointer, kn) {
+						currentKey = ffjtFieldErrorPointer
+						state = fflib.FFParse_want_colon
+						goto mainparse
+					}
+
+				case 'r':
+
+					if bytes.Equal(ffjKeyFieldErrorRule, kn) {
+						currentKey = ffjtFieldErrorRule
+						state = fflib.FFParse_want_colon
+						goto mainparse
+					}
+
+				case 'v':
+
+					if bytes.Equal(ffjKeyFieldErrorValue, kn) {
+						currentKey = ffjtFieldErrorValue
+						state = fflib.FFParse_want_colon
+						goto mainparse
+					}
+
+				}
+
+				if fflib.SimpleLetterEqualFold(ffjKeyFieldErrorValue, kn) {
+					currentKey = ffjtFieldErrorValue
+					state = fflib.FFParse_want_colon
+					goto mainparse
+				}
+
+				if fflib.EqualFoldRight(ffjKeyFieldErrorMessage, kn) {
+					currentKey = ffjtFieldErrorMessage
+					state = fflib.FFParse_want_colon
+					goto mainparse
+				}
+
+				if fflib.SimpleLetterEqualFold(ffjKeyFieldErrorRule, kn) {
+					currentKey = ffjtFieldErrorRule
+					state = fflib.FFParse_want_colon
+					goto mainparse
+				}
+
+				if fflib.SimpleLetterEqualFold(ffjKeyFieldErrorPointer, kn) {
+					currentKey = ffjtFieldErrorPointer
+					state = fflib.FFParse_want_colon
+					goto mainparse
+				}
+
+				currentKey = ffjtFieldErrornosuchkey
+				state = fflib.FFParse_want_colon
+				goto mainparse
+			}
+
+		case fflib.FFParse_want_colon:
+			if tok != fflib.FFTok_colon {
+				wantedTok = fflib.FFTok_colon
+				goto wrongtokenerror
+			}
+			state = fflib.FFParse_want_value
+			continue
+		case fflib.FFParse_want_value:
+
+			if tok == fflib.FFTok_left_brace || tok == fflib.FFTok_left_bracket || tok == fflib.FFTok_integer || tok == fflib.FFTok_double || tok == fflib.FFTok_string || tok == fflib.FFTok_bool || tok == fflib.FFTok_null {
+				switch currentKey {
+
+				case ffjtFieldErrorPointer:
+					goto handle_Pointer
+
+				case ffjtFieldErrorRule:
+					goto handle_Rule
+
+				case ffjtFieldErrorMessage:
+					goto handle_Message
+
+				case ffjtFieldErrorValue:
+					goto handle_Value
+
+				case ffjtFieldErrornosuchkey:
+					err = fs.SkipField(tok)
+					if err != nil {
+						return fs.WrapErr(err)
+					}
+					state = fflib.FFParse_after_value
+					goto mainparse
+				}
+			} else {
+				goto wantedvalue
+			}
+		}
+	}
+
+handle_Pointer:
+
+	/* handler: j.Pointer type=string kind=string quoted=false*/
+
+	{
+
+		{
+			if tok != fflib.FFTok_string && tok != fflib.FFTok_null {
+				return fs.WrapErr(fmt.Errorf("cannot unmarshal %s into Go value for string", tok))
+			}
+		}
+
+		if tok == fflib.FFTok_null {
+
+		} else {
+
+			outBuf := fs.Output.Bytes()
+
+			j.Pointer = string(string(outBuf))
+
+		}
+	}
+
+	state = fflib.FFParse_after_value
+	goto mainparse
+
+handle_Rule:
+
+	/* handler: j.Rule type=string kind=string quoted=false*/
+
+	{
+
+		{
+			if tok != fflib.FFTok_string && tok != fflib.FFTok_null {
+				return fs.WrapErr(fmt.Errorf("cannot unmarshal %s into Go value for string", tok))
+			}
+		}
+
+		if tok == fflib.FFTok_null {
+
+		} else {
+
+			outBuf := fs.Output.Bytes()
+
+			j.Rule = string(string(outBuf))
+
+		}
+	}
+
+	state = fflib.FFParse_after_value
+	goto mainparse
+
+handle_Message:
+
+	/* handler: j.Message type=string kind=string quoted=false*/
+
+	{
+
+		{
+			if tok != fflib.FFTok_string && tok != fflib.FFTok_null {
+				return fs.WrapErr(fmt.Errorf("cannot unmarshal %s into Go value for string", tok))
+			}
+		}
+
+		if tok == fflib.FFTok_null {
+
+		} else {
+
+			outBuf := fs.Output.Bytes()
+
+			j.Message = string(string(outBuf))
+
+		}
+	}
+
+	state = fflib.FFParse_after_value
+	goto mainparse
+
+handle_Value:
+
+	/* handler: j.Value type=interface {} kind=interface quoted=false*/
+
+	{
+		/* Falling back. type=interface {} kind=interface */
+		tbuf, err := fs.CaptureField(tok)
+		if err != nil {
+			return fs.WrapErr(err)
+		}
+
+		err = json.Unmarshal(tbuf, &j.Value)
+		if err != nil {
+			return fs.WrapErr(err)
+		}
+	}
+
+	state = fflib.FFParse_after_value
+	goto mainparse
+
+wantedvalue:
+	return fs.WrapErr(fmt.Errorf("wanted value token, but got token: %v", tok))
+wrongtokenerror:
+	return fs.WrapErr(fmt.Errorf("ffjson: wanted token: %v, but got token: %v output=%s", wantedTok, tok, fs.Output.String()))
+tokerror:
+	if fs.BigError != nil {
+		return fs.WrapErr(fs.BigError)
+	}
+	err = fs.Error.ToError()
+	if err != nil {
+		return fs.WrapErr(err)
+	}
+	panic("ffjson-generated: unreachable, please report bug.")
+done:
+
+	return nil
+}
+
+// MarshalJSON marshal bytes to json - template
+func (j *JSONError) MarshalJSON() ([]byte, error) {
+	var buf fflib.Buffer
+	if j == nil {
+		buf.WriteString("null")
+		return buf.Bytes(), nil
+	}
+	err := j.MarshalJSONBuf(&buf)
+	if err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// MarshalJSONBuf marshal buff to json - template
+func (j *JSONError) MarshalJSONBuf(buf fflib.EncodingBuffer) error {
+	if j == nil {
 		buf.WriteString("null")
 		return nil
 	}
@@ -33,29 +385,58 @@ func (mj *JSONError) MarshalJSONBuf(buf fflib.EncodingBuffer) error {
 	_ = obj
 	_ = err
 	buf.WriteString(`{ `)
-	if mj.Status != 0 {
+	if j.Status != 0 {
 		buf.WriteString(`"status":`)
-		fflib.FormatBits2(buf, uint64(mj.Status), 10, mj.Status < 0)
+		fflib.FormatBits2(buf, uint64(j.Status), 10, j.Status < 0)
 		buf.WriteByte(',')
 	}
-	if len(mj.Code) != 0 {
+	if len(j.Code) != 0 {
 		buf.WriteString(`"code":`)
-		fflib.WriteJsonString(buf, string(mj.Code))
+		fflib.WriteJsonString(buf, string(j.Code))
 		buf.WriteByte(',')
 	}
-	if len(mj.Type) != 0 {
+	if len(j.Type) != 0 {
 		buf.WriteString(`"type":`)
-		fflib.WriteJsonString(buf, string(mj.Type))
+		fflib.WriteJsonString(buf, string(j.Type))
 		buf.WriteByte(',')
 	}
-	if len(mj.Message) != 0 {
+	if len(j.Message) != 0 {
 		buf.WriteString(`"message":`)
-		fflib.WriteJsonString(buf, string(mj.Message))
+		fflib.WriteJsonString(buf, string(j.Message))
 		buf.WriteByte(',')
 	}
-	if len(mj.MoreInfo) != 0 {
+	if len(j.MoreInfo) != 0 {
 		buf.WriteString(`"moreInfo":`)
-		fflib.WriteJsonString(buf, string(mj.MoreInfo))
+		fflib.WriteJsonString(buf, string(j.MoreInfo))
+		buf.WriteByte(',')
+	}
+	if len(j.RequestID) != 0 {
+		buf.WriteString(`"requestId":`)
+		fflib.WriteJsonString(buf, string(j.RequestID))
+		buf.WriteByte(',')
+	}
+	if len(j.Fields) != 0 {
+		buf.WriteString(`"fields":`)
+		if j.Fields != nil {
+			buf.WriteString(`[`)
+			for i, v := range j.Fields {
+				if i != 0 {
+					buf.WriteString(`,`)
+				}
+
+				{
+
+					err = v.MarshalJSONBuf(buf)
+					if err != nil {
+						return err
+					}
+
+				}
+			}
+			buf.WriteString(`]`)
+		} else {
+			buf.WriteString(`null`)
+		}
 		buf.WriteByte(',')
 	}
 	buf.Rewind(1)
@@ -64,38 +445,48 @@ func (mj *JSONError) MarshalJSONBuf(buf fflib.EncodingBuffer) error {
 }
 
 const (
-	ffj_t_JSONErrorbase = iota
-	ffj_t_JSONErrorno_such_key
+	ffjtJSONErrorbase = iota
+	ffjtJSONErrornosuchkey
 
-	ffj_t_JSONError_Status
+	ffjtJSONErrorStatus
 
-	ffj_t_JSONError_Code
+	ffjtJSONErrorCode
 
-	ffj_t_JSONError_Type
+	ffjtJSONErrorType
 
-	ffj_t_JSONError_Message
+	ffjtJSONErrorMessage
 
-	ffj_t_JSONError_MoreInfo
+	ffjtJSONErrorMoreInfo
+
+	ffjtJSONErrorRequestID
+
+	ffjtJSONErrorFields
 )
 
-var ffj_key_JSONError_Status = []byte("status")
+var ffjKeyJSONErrorStatus = []byte("status")
+
+var ffjKeyJSONErrorCode = []byte("code")
 
-var ffj_key_JSONError_Code = []byte("code")
+var ffjKeyJSONErrorType = []byte("type")
 
-var ffj_key_JSONError_Type = []byte("type")
+var ffjKeyJSONErrorMessage = []byte("message")
 
-var ffj_key_JSONError_Message = []byte("message")
+var ffjKeyJSONErrorMoreInfo = []byte("moreInfo")
 
-var ffj_key_JSONError_MoreInfo = []byte("moreInfo")
+var ffjKeyJSONErrorRequestID = []byte("requestId")
 
-func (uj *JSONError) UnmarshalJSON(input []byte) error {
+var ffjKeyJSONErrorFields = []byte("fields")
+
+// UnmarshalJSON umarshall json - template of ffjson
+func (j *JSONError) UnmarshalJSON(input []byte) error {
 	fs := fflib.NewFFLexer(input)
-	return uj.UnmarshalJSONFFLexer(fs, fflib.FFParse_map_start)
+	return j.UnmarshalJSONFFLexer(fs, fflib.FFParse_map_start)
 }
 
-func (uj *JSONError) UnmarshalJSONFFLexer(fs *fflib.FFLexer, state fflib.FFParseState) error {
-	var err error = nil
-	currentKey := ffj_t_JSONErrorbase
+// UnmarshalJSONFFLexer fast json unmarshall - template ffjson
+func (j *JSONError) UnmarshalJSONFFLexer(fs *fflib.FFLexer, state fflib.FFParseState) error {
+	var err error
+	currentKey := ffjtJSONErrorbase
 	_ = currentKey
 	tok := fflib.FFTok_init
 	wantedTok := fflib.FFTok_init
@@ -141,7 +532,7 @@ mainparse:
 			kn := fs.Output.Bytes()
 			if len(kn) <= 0 {
 				// "" case. hrm.
-				currentKey = ffj_t_JSONErrorno_such_key
+				currentKey = ffjtJSONErrornosuchkey
 				state = fflib.FFParse_want_colon
 				goto mainparse
 			} else {
@@ -149,74 +540,102 @@ mainparse:
 
 				case 'c':
 
-					if bytes.Equal(ffj_key_JSONError_Code, kn) {
-						currentKey = ffj_t_JSONError_Code
+					if bytes.Equal(ffjKeyJSONErrorCode, kn) {
+						currentKey = ffjtJSONErrorCode
+						state = fflib.FFParse_want_colon
+						goto mainparse
+					}
+
+				case 'f':
+
+					if bytes.Equal(ffjKeyJSONErrorFields, kn) {
+						currentKey = ffjtJSONErrorFields
 						state = fflib.FFParse_want_colon
 						goto mainparse
 					}
 
 				case 'm':
 
-					if bytes.Equal(ffj_key_JSONError_Message, kn) {
-						currentKey = ffj_t_JSONError_Message
+					if bytes.Equal(ffjKeyJSONErrorMessage, kn) {
+						currentKey = ffjtJSONErrorMessage
 						state = fflib.FFParse_want_colon
 						goto mainparse
 
-					} else if bytes.Equal(ffj_key_JSONError_MoreInfo, kn) {
-						currentKey = ffj_t_JSONError_MoreInfo
+					} else if bytes.Equal(ffjKeyJSONErrorMoreInfo, kn) {
+						currentKey = ffjtJSONErrorMoreInfo
+						state = fflib.FFParse_want_colon
+						goto mainparse
+					}
+
+				case 'r':
+
+					if bytes.Equal(ffjKeyJSONErrorRequestID, kn) {
+						currentKey = ffjtJSONErrorRequestID
 						state = fflib.FFParse_want_colon
 						goto mainparse
 					}
 
 				case 's':
 
-					if bytes.Equal(ffj_key_JSONError_Status, kn) {
-						currentKey = ffj_t_JSONError_Status
+					if bytes.Equal(ffjKeyJSONErrorStatus, kn) {
+						currentKey = ffjtJSONErrorStatus
 						state = fflib.FFParse_want_colon
 						goto mainparse
 					}
 
 				case 't':
 
-					if bytes.Equal(ffj_key_JSONError_Type, kn) {
-						currentKey = ffj_t_JSONError_Type
+					if bytes.Equal(ffjKeyJSONErrorType, kn) {
+						currentKey = ffjtJSONErrorType
 						state = fflib.FFParse_want_colon
 						goto mainparse
 					}
 
 				}
 
-				if fflib.SimpleLetterEqualFold(ffj_key_JSONError_MoreInfo, kn) {
-					currentKey = ffj_t_JSONError_MoreInfo
+				if fflib.EqualFoldRight(ffjKeyJSONErrorFields, kn) {
+					currentKey = ffjtJSONErrorFields
+					state = fflib.FFParse_want_colon
+					goto mainparse
+				}
+
+				if fflib.EqualFoldRight(ffjKeyJSONErrorRequestID, kn) {
+					currentKey = ffjtJSONErrorRequestID
+					state = fflib.FFParse_want_colon
+					goto mainparse
+				}
+
+				if fflib.SimpleLetterEqualFold(ffjKeyJSONErrorMoreInfo, kn) {
+					currentKey = ffjtJSONErrorMoreInfo
 					state = fflib.FFParse_want_colon
 					goto mainparse
 				}
 
-				if fflib.EqualFoldRight(ffj_key_JSONError_Message, kn) {
-					currentKey = ffj_t_JSONError_Message
+				if fflib.EqualFoldRight(ffjKeyJSONErrorMessage, kn) {
+					currentKey = ffjtJSONErrorMessage
 					state = fflib.FFParse_want_colon
 					goto mainparse
 				}
 
-				if fflib.SimpleLetterEqualFold(ffj_key_JSONError_Type, kn) {
-					currentKey = ffj_t_JSONError_Type
+				if fflib.SimpleLetterEqualFold(ffjKeyJSONErrorType, kn) {
+					currentKey = ffjtJSONErrorType
 					state = fflib.FFParse_want_colon
 					goto mainparse
 				}
 
-				if fflib.SimpleLetterEqualFold(ffj_key_JSONError_Code, kn) {
-					currentKey = ffj_t_JSONError_Code
+				if fflib.SimpleLetterEqualFold(ffjKeyJSONErrorCode, kn) {
+					currentKey = ffjtJSONErrorCode
 					state = fflib.FFParse_want_colon
 					goto mainparse
 				}
 
-				if fflib.EqualFoldRight(ffj_key_JSONError_Status, kn) {
-					currentKey = ffj_t_JSONError_Status
+				if fflib.EqualFoldRight(ffjKeyJSONErrorStatus, kn) {
+					currentKey = ffjtJSONErrorStatus
 					state = fflib.FFParse_want_colon
 					goto mainparse
 				}
 
-				currentKey = ffj_t_JSONErrorno_such_key
+				currentKey = ffjtJSONErrornosuchkey
 				state = fflib.FFParse_want_colon
 				goto mainparse
 			}
@@ -233,22 +652,28 @@ mainparse:
 			if tok == fflib.FFTok_left_brace || tok == fflib.FFTok_left_bracket || tok == fflib.FFTok_integer || tok == fflib.FFTok_double || tok == fflib.FFTok_string || tok == fflib.FFTok_bool || tok == fflib.FFTok_null {
 				switch currentKey {
 
-				case ffj_t_JSONError_Status:
+				case ffjtJSONErrorStatus:
 					goto handle_Status
 
-				case ffj_t_JSONError_Code:
+				case ffjtJSONErrorCode:
 					goto handle_Code
 
-				case ffj_t_JSONError_Type:
+				case ffjtJSONErrorType:
 					goto handle_Type
 
-				case ffj_t_JSONError_Message:
+				case ffjtJSONErrorMessage:
 					goto handle_Message
 
-				case ffj_t_JSONError_MoreInfo:
+				case ffjtJSONErrorMoreInfo:
 					goto handle_MoreInfo
 
-				case ffj_t_JSONErrorno_such_key:
+				case ffjtJSONErrorRequestID:
+					goto handle_RequestID
+
+				case ffjtJSONErrorFields:
+					goto handle_Fields
+
+				case ffjtJSONErrornosuchkey:
 					err = fs.SkipField(tok)
 					if err != nil {
 						return fs.WrapErr(err)
@@ -264,7 +689,7 @@ mainparse:
 
 handle_Status:
 
-	/* handler: uj.Status type=int kind=int quoted=false*/
+	/* handler: j.Status type=int kind=int quoted=false*/
 
 	{
 		if tok != fflib.FFTok_integer && tok != fflib.FFTok_null {
@@ -284,7 +709,7 @@ handle_Status:
 				return fs.WrapErr(err)
 			}
 
-			uj.Status = int(tval)
+			j.Status = int(tval)
 
 		}
 	}
@@ -294,7 +719,7 @@ handle_Status:
 
 handle_Code:
 
-	/* handler: uj.Code type=string kind=string quoted=false*/
+	/* handler: j.Code type=string kind=string quoted=false*/
 
 	{
 
@@ -310,7 +735,7 @@ handle_Code:
 
 			outBuf := fs.Output.Bytes()
 
-			uj.Code = string(string(outBuf))
+			j.Code = string(string(outBuf))
 
 		}
 	}
@@ -320,7 +745,7 @@ handle_Code:
 
 handle_Type:
 
-	/* handler: uj.Type type=string kind=string quoted=false*/
+	/* handler: j.Type type=string kind=string quoted=false*/
 
 	{
 
@@ -336,7 +761,7 @@ handle_Type:
 
 			outBuf := fs.Output.Bytes()
 
-			uj.Type = string(string(outBuf))
+			j.Type = string(string(outBuf))
 
 		}
 	}
@@ -346,7 +771,7 @@ handle_Type:
 
 handle_Message:
 
-	/* handler: uj.Message type=string kind=string quoted=false*/
+	/* handler: j.Message type=string kind=string quoted=false*/
 
 	{
 
@@ -362,7 +787,7 @@ handle_Message:
 
 			outBuf := fs.Output.Bytes()
 
-			uj.Message = string(string(outBuf))
+			j.Message = string(string(outBuf))
 
 		}
 	}
@@ -372,7 +797,7 @@ handle_Message:
 
 handle_MoreInfo:
 
-	/* handler: uj.MoreInfo type=string kind=string quoted=false*/
+	/* handler: j.MoreInfo type=string kind=string quoted=false*/
 
 	{
 
@@ -388,7 +813,7 @@ handle_MoreInfo:
 
 			outBuf := fs.Output.Bytes()
 
-			uj.MoreInfo = string(string(outBuf))
+			j.MoreInfo = string(string(outBuf))
 
 		}
 	}
@@ -396,6 +821,100 @@ handle_MoreInfo:
 	state = fflib.FFParse_after_value
 	goto mainparse
 
+handle_RequestID:
+
+	/* handler: j.RequestID type=string kind=string quoted=false*/
+
+	{
+
+		{
+			if tok != fflib.FFTok_string && tok != fflib.FFTok_null {
+				return fs.WrapErr(fmt.Errorf("cannot unmarshal %s into Go value for string", tok))
+			}
+		}
+
+		if tok == fflib.FFTok_null {
+
+		} else {
+
+			outBuf := fs.Output.Bytes()
+
+			j.RequestID = string(string(outBuf))
+
+		}
+	}
+
+	state = fflib.FFParse_after_value
+	goto mainparse
+
+handle_Fields:
+
+	/* handler: j.Fields type=[]web.FieldError kind=slice quoted=false*/
+
+	{
+
+		{
+			if tok != fflib.FFTok_left_brace && tok != fflib.FFTok_null {
+				return fs.WrapErr(fmt.Errorf("cannot unmarshal %s into Go value for ", tok))
+			}
+		}
+
+		if tok == fflib.FFTok_null {
+			j.Fields = nil
+		} else {
+
+			j.Fields = []FieldError{}
+
+			wantVal := true
+
+			for {
+
+				var tmpJFields FieldError
+
+				tok = fs.Scan()
+				if tok == fflib.FFTok_error {
+					goto tokerror
+				}
+				if tok == fflib.FFTok_right_brace {
+					break
+				}
+
+				if tok == fflib.FFTok_comma {
+					if wantVal == true {
+						// TODO(pquerna): this isn't an ideal error message, this handles
+						// things like [,,,] as an array value.
+						return fs.WrapErr(fmt.Errorf("wanted value token, but got token: %v", tok))
+					}
+					continue
+				} else {
+					wantVal = true
+				}
+
+				/* handler: tmpJFields type=web.FieldError kind=struct quoted=false*/
+
+				{
+					if tok == fflib.FFTok_null {
+
+					} else {
+
+						err = tmpJFields.UnmarshalJSONFFLexer(fs, fflib.FFParse_want_key)
+						if err != nil {
+							return err
+						}
+					}
+					state = fflib.FFParse_after_value
+				}
+
+				j.Fields = append(j.Fields, tmpJFields)
+
+				wantVal = false
+			}
+		}
+	}
+
+	state = fflib.FFParse_after_value
+	goto mainparse
+
 wantedvalue:
 	return fs.WrapErr(fmt.Errorf("wanted value token, but got token: %v", tok))
 wrongtokenerror:
@@ -410,5 +929,6 @@ tokerror:
 	}
 	panic("ffjson-generated: unreachable, please report bug.")
 done:
+
 	return nil
 }
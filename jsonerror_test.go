@@ -0,0 +1,54 @@
+/*
+ * Copyright 2016 Fabrício Godoy
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package web
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestJSONErrorFields(t *testing.T) {
+	jerr := NewJSONError().
+		Status(StatusUnprocessableEntity).
+		Message("Invalid input").
+		Field(JSONPointer("name"), "required", "Name is required", "").
+		Field(JSONPointer("items", "0", "a/b~c"), "min", "Too small", 0.5).
+		Build()
+
+	data, err := json.Marshal(jerr)
+	if err != nil {
+		t.Fatalf("Error encoding JSONError: %v", err)
+	}
+
+	var decoded JSONError
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("Error decoding JSONError: %v", err)
+	}
+	if len(decoded.Fields) != 2 {
+		t.Fatalf("Unexpected fields decoded: %s", data)
+	}
+
+	expected := []FieldError{
+		{"/name", "required", "Name is required", ""},
+		{"/items/0/a~1b~0c", "min", "Too small", 0.5},
+	}
+	for i, v := range expected {
+		if decoded.Fields[i] != v {
+			t.Errorf("Unexpected field #%d: %#v", i, decoded.Fields[i])
+		}
+	}
+}
@@ -29,6 +29,10 @@ type JSONErrorBuilder interface {
 	// CustomError sets current instance from specified error.
 	CustomError(code, errorType, msg string) JSONErrorBuilder
 
+	// Field adds an error of a specific input field, identified by a JSON
+	// pointer.
+	Field(pointer, rule, msg string, value interface{}) JSONErrorBuilder
+
 	// FromError sets current instance based on native error.
 	FromError(e error) JSONErrorBuilder
 
@@ -66,6 +70,19 @@ func (b *jsonErrorBuilder) CustomError(code, errorType, msg string) JSONErrorBui
 	return b
 }
 
+func (b *jsonErrorBuilder) Field(
+	pointer, rule, msg string,
+	value interface{},
+) JSONErrorBuilder {
+	b.instance.Fields = append(b.instance.Fields, FieldError{
+		Pointer: pointer,
+		Rule:    rule,
+		Message: msg,
+		Value:   value,
+	})
+	return b
+}
+
 func (b *jsonErrorBuilder) FromError(e error) JSONErrorBuilder {
 	errType := reflect.TypeOf(e)
 	var typeName string
@@ -0,0 +1,317 @@
+/*
+ * Copyright 2016 Fabrício Godoy
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package web
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+)
+
+const (
+	// JSONErrorCodeMalformed defines the JSONError code when content is not
+	// valid JSON or does not match target object.
+	JSONErrorCodeMalformed = "malformed_json"
+	// JSONErrorCodeUnknownField defines the JSONError code when content has a
+	// field which is not defined by target object.
+	JSONErrorCodeUnknownField = "unknown_field"
+	// JSONErrorCodeTrailingData defines the JSONError code when content has
+	// data after first JSON value.
+	JSONErrorCodeTrailingData = "trailing_data"
+	// JSONErrorCodeDuplicateKey defines the JSONError code when content has an
+	// object with duplicated keys.
+	JSONErrorCodeDuplicateKey = "duplicate_key"
+	// JSONErrorCodeTooLarge defines the JSONError code when content is larger
+	// than maximum length.
+	JSONErrorCodeTooLarge = "too_large"
+
+	// maxDrainLength defines how much unread content is discarded before body
+	// is closed, allowing connection to be reused.
+	maxDrainLength = 256 << 10
+)
+
+// A JSONReader represents options to read client sent content using JSON
+// decoding.
+type JSONReader struct {
+	// MaxLength defines the maximum length of content. A Request Entity Too
+	// Large (413) JSONError is written when content is larger.
+	MaxLength int64
+	// DisallowUnknownFields defines whether fields not defined by target
+	// object are rejected.
+	DisallowUnknownFields bool
+	// UseNumber defines whether numbers are decoded to interface values as
+	// json.Number instead of float64.
+	UseNumber bool
+	// DisallowTrailingData defines whether data after first JSON value is
+	// rejected.
+	DisallowTrailingData bool
+	// DisallowDuplicateKeys defines whether objects with duplicated keys are
+	// rejected. The whole content is buffered to be checked.
+	DisallowDuplicateKeys bool
+	// Validate defines whether decoded object is validated, as defined by
+	// Validate function. An Unprocessable Entity (422) JSONError listing
+	// invalid fields is written when validation fails.
+	Validate bool
+}
+
+// Decode tries to read request body using JSON decoding and writes it to
+// object pointed to by obj, without writing any response.
+//
+// Returns nil whether no error occurred; otherwise, a *JSONError whose status
+// defines the HTTP status that should be answered: Unsupported Media Type
+// (415) when content type is not JSON-compatible; Request Entity Too Large
+// (413) when content is larger than maximum length; and Unprocessable Entity
+// (422) when content is rejected or is not valid.
+func (jr JSONReader) Decode(r *http.Request, obj interface{}) error {
+	if jerr := checkJSONContentType(r); jerr != nil {
+		return jerr
+	}
+	if r.ContentLength > jr.MaxLength {
+		return tooLargeError(jr.MaxLength)
+	}
+
+	return jr.DecodeBody(r.Body, obj)
+}
+
+// DecodeBody tries to read client sent content using JSON decoding and writes
+// it to object pointed to by obj, without writing any response.
+//
+// Returns nil whether no error occurred; otherwise, a *JSONError as returned
+// by Decode. Body is always drained, up to a bound, and closed.
+func (jr JSONReader) DecodeBody(body io.ReadCloser, obj interface{}) error {
+	jerr := jr.decode(body, obj)
+	io.CopyN(ioutil.Discard, body, maxDrainLength)
+	closeErr := body.Close()
+	if jerr != nil {
+		return jerr
+	}
+
+	if closeErr != nil {
+		return NewJSONError().
+			FromError(closeErr).
+			Build()
+	}
+
+	if jr.Validate {
+		if fields := Validate(obj); len(fields) > 0 {
+			return validationError(fields)
+		}
+	}
+
+	return nil
+}
+
+// ReadRequest tries to read request body using JSON decoding and writes it to
+// object pointed to by obj, as JSONReadRequest does.
+func (jr JSONReader) ReadRequest(
+	r *http.Request,
+	obj interface{},
+	w http.ResponseWriter,
+) bool {
+	return writeDecodeError(w, jr.Decode(r, obj))
+}
+
+// Read tries to read client sent content using JSON decoding and writes it to
+// object pointed to by obj, as JSONRead does.
+//
+// An Unprocessable Entity (422) JSONError is written when content is rejected
+// by any option, whose code identifies the failure.
+func (jr JSONReader) Read(
+	body io.ReadCloser,
+	obj interface{},
+	w http.ResponseWriter,
+) bool {
+	return writeDecodeError(w, jr.DecodeBody(body, obj))
+}
+
+// writeDecodeError writes specified error returned by decoding, when defined.
+//
+// Returns true whether no error is defined.
+func writeDecodeError(w http.ResponseWriter, err error) bool {
+	if err == nil {
+		return true
+	}
+
+	jerr := err.(*JSONError)
+	writeError(w, jerr)
+	return false
+}
+
+func (jr JSONReader) decode(body io.Reader, obj interface{}) *JSONError {
+	var src io.Reader = &maxLengthReader{body, jr.MaxLength}
+	if jr.DisallowDuplicateKeys {
+		data, err := ioutil.ReadAll(src)
+		if err == errTooLarge {
+			return tooLargeError(jr.MaxLength)
+		}
+		if err != nil {
+			return NewJSONError().FromError(err).Build()
+		}
+		if key, ok := findDuplicateKey(data); ok {
+			return decodeError(JSONErrorCodeDuplicateKey,
+				fmt.Sprintf("The object key '%s' is duplicated", key))
+		}
+		src = bytes.NewReader(data)
+	}
+
+	dec := json.NewDecoder(src)
+	if jr.DisallowUnknownFields {
+		dec.DisallowUnknownFields()
+	}
+	if jr.UseNumber {
+		dec.UseNumber()
+	}
+
+	if err := dec.Decode(obj); err != nil {
+		if err == errTooLarge {
+			return tooLargeError(jr.MaxLength)
+		}
+
+		jerr := NewJSONError().
+			FromError(err).
+			Status(StatusUnprocessableEntity).
+			Build()
+		jerr.Code = JSONErrorCodeMalformed
+		if strings.HasPrefix(err.Error(), "json: unknown field ") {
+			jerr.Code = JSONErrorCodeUnknownField
+		}
+		return jerr
+	}
+
+	if jr.DisallowTrailingData {
+		_, err := dec.Token()
+		if err == errTooLarge {
+			return tooLargeError(jr.MaxLength)
+		}
+		if err != io.EOF {
+			return decodeError(JSONErrorCodeTrailingData,
+				"The content has data after JSON value")
+		}
+	}
+
+	// Decoder stops reading after first value, content past it must still
+	// be within maximum length
+	if _, err := io.Copy(ioutil.Discard, src); err == errTooLarge {
+		return tooLargeError(jr.MaxLength)
+	}
+
+	return nil
+}
+
+func decodeError(code, msg string) *JSONError {
+	return NewJSONError().
+		CustomError(code, "DecodeError", msg).
+		Status(StatusUnprocessableEntity).
+		Build()
+}
+
+func tooLargeError(maxlen int64) *JSONError {
+	return NewJSONError().
+		CustomError(JSONErrorCodeTooLarge, "DecodeError",
+			fmt.Sprintf("The content is larger than %d bytes", maxlen)).
+		Status(http.StatusRequestEntityTooLarge).
+		Build()
+}
+
+// errTooLarge is returned by maxLengthReader when content is larger than
+// maximum length.
+var errTooLarge = errors.New("content is larger than maximum length")
+
+// A maxLengthReader represents a Reader that fails when more than n bytes can
+// be read, unlike io.LimitedReader which silently truncates content.
+type maxLengthReader struct {
+	r io.Reader
+	n int64
+}
+
+func (l *maxLengthReader) Read(p []byte) (int, error) {
+	if l.n < 0 {
+		return 0, errTooLarge
+	}
+	if int64(len(p)) > l.n+1 {
+		p = p[:l.n+1]
+	}
+
+	n, err := l.r.Read(p)
+	l.n -= int64(n)
+	if l.n < 0 {
+		return n + int(l.n), errTooLarge
+	}
+	return n, err
+}
+
+// A jsonFrame represents an object or array being scanned.
+type jsonFrame struct {
+	keys      map[string]bool
+	expectKey bool
+}
+
+// findDuplicateKey scans specified JSON data and returns the first key which
+// is duplicated in a same object. Malformed data is left to be reported by
+// decoder.
+func findDuplicateKey(data []byte) (string, bool) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	stack := make([]*jsonFrame, 0)
+	valueDone := func() {
+		if len(stack) > 0 && stack[len(stack)-1].keys != nil {
+			stack[len(stack)-1].expectKey = true
+		}
+	}
+
+	for {
+		tok, err := dec.Token()
+		if err != nil {
+			return "", false
+		}
+
+		if len(stack) > 0 && stack[len(stack)-1].expectKey {
+			top := stack[len(stack)-1]
+			if key, ok := tok.(string); ok {
+				if top.keys[key] {
+					return key, true
+				}
+				top.keys[key] = true
+				top.expectKey = false
+				continue
+			}
+		}
+
+		switch tok {
+		case json.Delim('{'):
+			stack = append(stack, &jsonFrame{
+				keys:      make(map[string]bool),
+				expectKey: true,
+			})
+			continue
+		case json.Delim('['):
+			stack = append(stack, &jsonFrame{})
+			continue
+		case json.Delim('}'), json.Delim(']'):
+			stack = stack[:len(stack)-1]
+		}
+		valueDone()
+
+		if len(stack) == 0 {
+			return "", false
+		}
+	}
+}
@@ -0,0 +1,149 @@
+/*
+ * Copyright 2016 Fabrício Godoy
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package web
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestJSONReaderOptions(t *testing.T) {
+	strict := JSONReader{
+		MaxLength:             BodyMaxLength,
+		DisallowUnknownFields: true,
+		DisallowTrailingData:  true,
+		DisallowDuplicateKeys: true,
+	}
+	testValues := []struct {
+		reader JSONReader
+		body   string
+		code   string
+	}{
+		{strict, `{"Number":40,"Text":"a"}`, ""},
+		{strict, `{"Number":40} `, ""},
+		{strict, `{"Number":`, JSONErrorCodeMalformed},
+		{strict, `{"Number":40,"Other":1}`, JSONErrorCodeUnknownField},
+		{strict, `{"Number":40} {}`, JSONErrorCodeTrailingData},
+		{strict, `{"Number":40}garbage`, JSONErrorCodeTrailingData},
+		{strict, `{"Number":40,"Number":41}`, JSONErrorCodeDuplicateKey},
+		{JSONReader{MaxLength: BodyMaxLength},
+			`{"Number":40,"Other":1,"Number":41} {}`, ""},
+	}
+
+	for _, v := range testValues {
+		resp := httptest.NewRecorder()
+		var foo Foo
+		ok := v.reader.Read(nopCloser{strings.NewReader(v.body)}, &foo, resp)
+		if ok != (len(v.code) == 0) {
+			t.Errorf("Unexpected result for '%s': %v", v.body, ok)
+			continue
+		}
+		if ok {
+			continue
+		}
+
+		var jerr JSONError
+		if err := json.NewDecoder(resp.Body).Decode(&jerr); err != nil {
+			t.Fatalf("Error decoding JSONError: %v", err)
+		}
+		if jerr.Code != v.code || jerr.Status != StatusUnprocessableEntity {
+			t.Errorf("Unexpected error for '%s': %#v", v.body, jerr)
+		}
+	}
+}
+
+func TestJSONReaderNestedDuplicateKeys(t *testing.T) {
+	testValues := []struct {
+		body      string
+		duplicate string
+	}{
+		{`{"a":{"b":1},"c":{"b":2}}`, ""},
+		{`[{"a":1},{"a":2}]`, ""},
+		{`{"a":[{"b":1,"b":2}]}`, "b"},
+		{`{"a":{"b":[1,2]},"a":3}`, "a"},
+	}
+
+	for _, v := range testValues {
+		key, ok := findDuplicateKey([]byte(v.body))
+		if ok != (len(v.duplicate) > 0) || key != v.duplicate {
+			t.Errorf("Unexpected duplicated key for '%s': '%s'", v.body, key)
+		}
+	}
+}
+
+func TestJSONReaderUseNumber(t *testing.T) {
+	var obj map[string]interface{}
+	reader := JSONReader{MaxLength: BodyMaxLength, UseNumber: true}
+	body := nopCloser{strings.NewReader(`{"n":9007199254740993}`)}
+	if !reader.Read(body, &obj, httptest.NewRecorder()) {
+		t.Fatal("Content should be read")
+	}
+
+	n, ok := obj["n"].(json.Number)
+	if !ok || n.String() != "9007199254740993" {
+		t.Errorf("Number should be kept as json.Number: %#v", obj["n"])
+	}
+}
+
+func TestJSONReaderTooLarge(t *testing.T) {
+	body := `{"Text":"` + strings.Repeat("a", 100) + `"}`
+	testValues := []struct {
+		reader JSONReader
+		body   string
+		ok     bool
+	}{
+		{JSONReader{MaxLength: int64(len(body))}, body, true},
+		{JSONReader{MaxLength: int64(len(body) - 1)}, body, false},
+		{JSONReader{MaxLength: 20}, body, false},
+		{JSONReader{MaxLength: 20, DisallowDuplicateKeys: true}, body, false},
+		{JSONReader{MaxLength: 20}, `{"Number":40}` + body, false},
+		{JSONReader{MaxLength: 20}, `{"Number":40}   `, true},
+	}
+
+	for i, v := range testValues {
+		resp := httptest.NewRecorder()
+		var foo Foo
+		ok := v.reader.Read(nopCloser{strings.NewReader(v.body)}, &foo, resp)
+		if ok != v.ok {
+			t.Errorf("Unexpected result on test #%d: %v", i, ok)
+		}
+		if !ok && resp.Code != http.StatusRequestEntityTooLarge {
+			t.Errorf("Unexpected status on test #%d: %d", i, resp.Code)
+		}
+	}
+}
+
+func TestJSONReadRequestContentLength(t *testing.T) {
+	body := &countingReader{Reader: strings.NewReader(`{"Number":40}`)}
+	req := httptest.NewRequest("POST", "/", body)
+	req.ContentLength = 1024
+	resp := httptest.NewRecorder()
+
+	var foo Foo
+	if JSONReadRequest(req, 100, &foo, resp) {
+		t.Error("Request larger than maximum length should be rejected")
+	}
+	if resp.Code != http.StatusRequestEntityTooLarge {
+		t.Errorf("Unexpected status: %d", resp.Code)
+	}
+	if body.reads > 0 {
+		t.Error("Body should not be read when Content-Length is too large")
+	}
+}
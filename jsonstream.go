@@ -0,0 +1,285 @@
+/*
+ * Copyright 2016 Fabrício Godoy
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package web
+
+import (
+	"net/http"
+	"sync"
+	"time"
+)
+
+// A JSONStreamFormat defines how a stream of values is serialized.
+type JSONStreamFormat int
+
+const (
+	// JSONStreamArray serializes values as elements of a JSON array.
+	JSONStreamArray JSONStreamFormat = iota
+	// JSONStreamNDJSON serializes values as newline-delimited JSON, one value
+	// per line.
+	JSONStreamNDJSON
+)
+
+// A JSONNextFunc represents a function that returns the next value to be
+// streamed. The stream ends when ok is false or an error is returned.
+type JSONNextFunc func() (value interface{}, ok bool, err error)
+
+// A JSONStreamer represents options to stream values to client using JSON
+// encoding, without holding whole content in memory.
+//
+// The HTTP status is written before first value, so an error occurred while
+// streaming cannot be reported to client. A JSON array is left unterminated
+// in such case, allowing client to detect the failure.
+type JSONStreamer struct {
+	// Format defines how values are serialized.
+	Format JSONStreamFormat
+	// FlushInterval defines the interval between flushes of written values,
+	// when supported by response. Pending values are flushed periodically,
+	// even while next value is awaited. Values are flushed as soon as written
+	// when zero.
+	FlushInterval time.Duration
+}
+
+// Write sets response content type and HTTP status, and streams values
+// returned by next until it ends.
+//
+// Returns the request context error when client disconnects before stream
+// ends, or the error returned by next or by serialization.
+func (s JSONStreamer) Write(
+	w http.ResponseWriter,
+	r *http.Request,
+	status int,
+	next JSONNextFunc,
+) error {
+	ctx := r.Context()
+	sw := s.begin(w, status)
+	defer sw.stop()
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		v, ok, err := next()
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return sw.end()
+		}
+		if err := sw.write(v); err != nil {
+			return err
+		}
+	}
+}
+
+// WriteChan sets response content type and HTTP status, and streams values
+// received from specified channel until it is closed. Written values are
+// flushed whenever no value is ready to be received.
+//
+// Returns the request context error when client disconnects before channel is
+// closed, then the channel is no longer received from; therefore, senders
+// should also watch the request context.
+func (s JSONStreamer) WriteChan(
+	w http.ResponseWriter,
+	r *http.Request,
+	status int,
+	values <-chan interface{},
+) error {
+	ctx := r.Context()
+	sw := s.begin(w, status)
+	defer sw.stop()
+	for {
+		var v interface{}
+		var ok bool
+		select {
+		case v, ok = <-values:
+		default:
+			sw.flush()
+			select {
+			case v, ok = <-values:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+
+		if !ok {
+			return sw.end()
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := sw.write(v); err != nil {
+			return err
+		}
+	}
+}
+
+// JSONStreamWrite sets response content type to JSON, sets HTTP status and
+// streams values received from specified channel as elements of a JSON array,
+// as JSONStreamer does.
+func JSONStreamWrite(
+	w http.ResponseWriter,
+	r *http.Request,
+	status int,
+	values <-chan interface{},
+) error {
+	return JSONStreamer{Format: JSONStreamArray}.WriteChan(w, r, status, values)
+}
+
+// NDJSONWrite sets response content type to newline-delimited JSON, sets HTTP
+// status and streams values received from specified channel, one per line, as
+// JSONStreamer does.
+func NDJSONWrite(
+	w http.ResponseWriter,
+	r *http.Request,
+	status int,
+	values <-chan interface{},
+) error {
+	return JSONStreamer{Format: JSONStreamNDJSON}.WriteChan(w, r, status, values)
+}
+
+// A jsonStreamWriter represents the state of a stream being written. Pending
+// values are flushed periodically, while next value is awaited, when a flush
+// interval is defined.
+type jsonStreamWriter struct {
+	mutex     sync.Mutex
+	w         http.ResponseWriter
+	format    JSONStreamFormat
+	interval  time.Duration
+	count     int
+	pending   bool
+	lastFlush time.Time
+	stopped   chan struct{}
+	done      chan struct{}
+}
+
+func (s JSONStreamer) begin(
+	w http.ResponseWriter,
+	status int,
+) *jsonStreamWriter {
+	contentType := NewHeader().ContentType().JSON()
+	if s.Format == JSONStreamNDJSON {
+		contentType = NewHeader().ContentType().NDJSON()
+	}
+	contentType.Write(w.Header())
+	w.WriteHeader(status)
+
+	sw := &jsonStreamWriter{
+		w:         w,
+		format:    s.Format,
+		interval:  s.FlushInterval,
+		lastFlush: time.Now(),
+	}
+	if _, ok := w.(http.Flusher); ok && sw.interval > 0 {
+		sw.stopped = make(chan struct{})
+		sw.done = make(chan struct{})
+		go sw.flushLoop()
+	}
+	return sw
+}
+
+// flushLoop flushes pending values every interval until stream is stopped.
+func (sw *jsonStreamWriter) flushLoop() {
+	defer close(sw.done)
+	ticker := time.NewTicker(sw.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			sw.flush()
+		case <-sw.stopped:
+			return
+		}
+	}
+}
+
+// stop stops periodic flushing, so response is not used after handler
+// returns.
+func (sw *jsonStreamWriter) stop() {
+	if sw.stopped == nil {
+		return
+	}
+	close(sw.stopped)
+	<-sw.done
+}
+
+func (sw *jsonStreamWriter) write(v interface{}) error {
+	data, err := marshalJSON(v)
+	if err != nil {
+		return err
+	}
+
+	sw.mutex.Lock()
+	defer sw.mutex.Unlock()
+	if sw.format == JSONStreamArray {
+		// Replaces newline written by marshalJSON by element separator
+		data = data[:len(data)-1]
+		sep := ",\n"
+		if sw.count == 0 {
+			sep = "[\n"
+		}
+		if _, err := sw.w.Write([]byte(sep)); err != nil {
+			return err
+		}
+	}
+	if _, err := sw.w.Write(data); err != nil {
+		return err
+	}
+
+	sw.count++
+	sw.pending = true
+	if time.Since(sw.lastFlush) >= sw.interval {
+		sw.flushLocked()
+	}
+	return nil
+}
+
+func (sw *jsonStreamWriter) end() error {
+	sw.mutex.Lock()
+	defer sw.mutex.Unlock()
+	if sw.format == JSONStreamArray {
+		tail := "\n]\n"
+		if sw.count == 0 {
+			tail = "[]\n"
+		}
+		if _, err := sw.w.Write([]byte(tail)); err != nil {
+			return err
+		}
+		sw.pending = true
+	}
+
+	sw.flushLocked()
+	return nil
+}
+
+// flush sends written values to client, when supported by response.
+func (sw *jsonStreamWriter) flush() {
+	sw.mutex.Lock()
+	defer sw.mutex.Unlock()
+	sw.flushLocked()
+}
+
+func (sw *jsonStreamWriter) flushLocked() {
+	if !sw.pending {
+		return
+	}
+	if f, ok := sw.w.(http.Flusher); ok {
+		f.Flush()
+	}
+	sw.pending = false
+	sw.lastFlush = time.Now()
+}
@@ -0,0 +1,192 @@
+/*
+ * Copyright 2016 Fabrício Godoy
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package web
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestJSONStreamWrite(t *testing.T) {
+	testValues := [][]int{
+		{},
+		{1},
+		{1, 2, 3},
+	}
+
+	for _, v := range testValues {
+		values := make(chan interface{})
+		go func() {
+			defer close(values)
+			for _, i := range v {
+				values <- Foo{Number: i}
+			}
+		}()
+
+		r := httptest.NewRequest("GET", "/", nil)
+		w := httptest.NewRecorder()
+		if err := JSONStreamWrite(w, r, http.StatusOK, values); err != nil {
+			t.Fatalf("Error on JSONStreamWrite: %v", err)
+		}
+
+		var foos []Foo
+		if err := json.Unmarshal(w.Body.Bytes(), &foos); err != nil {
+			t.Fatalf("Error decoding array '%s': %v", w.Body.String(), err)
+		}
+		if len(foos) != len(v) {
+			t.Fatalf("Expected %d elements but got %d", len(v), len(foos))
+		}
+		for i := range v {
+			if foos[i].Number != v[i] {
+				t.Errorf("Unexpected element %d: %#v", i, foos[i])
+			}
+		}
+		if !w.Flushed {
+			t.Error("Response should be flushed")
+		}
+		if ct := w.Header().Get(headerNameContentType); ct !=
+			NewHeader().ContentType().JSON().Value {
+			t.Errorf("Unexpected content type: %s", ct)
+		}
+	}
+}
+
+func TestNDJSONWrite(t *testing.T) {
+	values := make(chan interface{}, 3)
+	for i := 1; i <= 3; i++ {
+		values <- Foo{Number: i}
+	}
+	close(values)
+
+	r := httptest.NewRequest("GET", "/", nil)
+	w := httptest.NewRecorder()
+	if err := NDJSONWrite(w, r, http.StatusOK, values); err != nil {
+		t.Fatalf("Error on NDJSONWrite: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSuffix(w.Body.String(), "\n"), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("Expected 3 lines but got: %q", w.Body.String())
+	}
+	for i, line := range lines {
+		var foo Foo
+		if err := json.Unmarshal([]byte(line), &foo); err != nil {
+			t.Fatalf("Error decoding line '%s': %v", line, err)
+		}
+		if foo.Number != i+1 {
+			t.Errorf("Unexpected value on line %d: %#v", i+1, foo)
+		}
+	}
+	if ct := w.Header().Get(headerNameContentType); ct !=
+		NewHeader().ContentType().NDJSON().Value {
+		t.Errorf("Unexpected content type: %s", ct)
+	}
+}
+
+func TestJSONStreamerWrite(t *testing.T) {
+	failure := errors.New("failure")
+	i := 0
+	next := func() (interface{}, bool, error) {
+		i++
+		if i > 2 {
+			return nil, false, failure
+		}
+		return i, true, nil
+	}
+
+	r := httptest.NewRequest("GET", "/", nil)
+	w := httptest.NewRecorder()
+	err := JSONStreamer{}.Write(w, r, http.StatusOK, next)
+	if err != failure {
+		t.Errorf("Expected iterator error but got %v", err)
+	}
+	if w.Body.String() != "[\n1,\n2" {
+		t.Errorf("Unexpected unterminated array: %q", w.Body.String())
+	}
+}
+
+func TestJSONStreamWriteDisconnect(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	r := httptest.NewRequest("GET", "/", nil).WithContext(ctx)
+	values := make(chan interface{})
+	go func() {
+		values <- 1
+		values <- 2
+		cancel()
+	}()
+
+	w := httptest.NewRecorder()
+	err := JSONStreamWrite(w, r, http.StatusOK, values)
+	if err != context.Canceled {
+		t.Errorf("Expected context error but got %v", err)
+	}
+	if !strings.HasPrefix(w.Body.String(), "[\n1") ||
+		strings.HasSuffix(w.Body.String(), "]\n") {
+		t.Errorf("Unexpected written content: %q", w.Body.String())
+	}
+}
+
+type signalFlusher struct {
+	*httptest.ResponseRecorder
+	flushed chan struct{}
+}
+
+func (f *signalFlusher) Flush() {
+	select {
+	case f.flushed <- struct{}{}:
+	default:
+	}
+}
+
+func TestJSONStreamerPeriodicFlush(t *testing.T) {
+	w := &signalFlusher{httptest.NewRecorder(), make(chan struct{})}
+	i := 0
+	next := func() (interface{}, bool, error) {
+		i++
+		switch i {
+		case 1:
+			return 1, true, nil
+		case 2:
+			// Pending value must be flushed while next value is awaited
+			select {
+			case <-w.flushed:
+			case <-time.After(time.Second):
+				t.Error("Pending value was not flushed")
+			}
+			return 2, true, nil
+		}
+		return nil, false, nil
+	}
+
+	r := httptest.NewRequest("GET", "/", nil)
+	streamer := JSONStreamer{
+		Format:        JSONStreamNDJSON,
+		FlushInterval: 10 * time.Millisecond,
+	}
+	if err := streamer.Write(w, r, http.StatusOK, next); err != nil {
+		t.Fatalf("Error on Write: %v", err)
+	}
+	if w.Body.String() != "1\n2\n" {
+		t.Errorf("Unexpected written content: %q", w.Body.String())
+	}
+}
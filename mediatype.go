@@ -0,0 +1,111 @@
+/*
+ * Copyright 2016 Fabrício Godoy
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package web
+
+import (
+	"mime"
+	"strings"
+)
+
+// A MediaType represents a parsed media type, as used by Content-Type HTTP
+// header.
+type MediaType struct {
+	// Top-level type, such as "application", or an asterisk.
+	Type string
+	// Subtype, such as "vnd.foo+json", or an asterisk.
+	Subtype string
+	// Structured syntax suffix of subtype, such as "json", or empty.
+	Suffix string
+	// Media type parameters, whose names are lower-cased.
+	Params map[string]string
+}
+
+// ParseMediaType parses specified media type value, as sent by Content-Type
+// HTTP header.
+//
+// Errors:
+// HeaderValueError when value is not a valid media type.
+func ParseMediaType(value string) (MediaType, error) {
+	return parseMediaType(headerNameContentType, value)
+}
+
+// parseMediaType parses specified media type value, as sent by specified HTTP
+// header.
+func parseMediaType(name, value string) (MediaType, error) {
+	mt, params, err := mime.ParseMediaType(value)
+	if err != nil {
+		return MediaType{}, &HeaderValueError{
+			name, value, "media type"}
+	}
+	if mt == "*" {
+		mt = "*/*"
+	}
+
+	slash := strings.IndexByte(mt, '/')
+	if slash < 0 {
+		return MediaType{}, &HeaderValueError{
+			name, value, "media type"}
+	}
+
+	result := MediaType{
+		Type:    mt[:slash],
+		Subtype: mt[slash+1:],
+		Params:  params,
+	}
+	if plus := strings.LastIndexByte(result.Subtype, '+'); plus >= 0 {
+		result.Suffix = result.Subtype[plus+1:]
+	}
+	return result, nil
+}
+
+// IsJSON returns whether current media type is JSON or uses the JSON
+// structured syntax suffix, as "application/problem+json".
+func (m MediaType) IsJSON() bool {
+	return m.Suffix == "json" ||
+		(m.Type == "application" && m.Subtype == "json")
+}
+
+// Match returns whether current media type matches specified pattern. The
+// pattern type and subtype can be asterisks, a subtype as "*+json" matches any
+// subtype with specified suffix, and every pattern parameter must be matched.
+func (m MediaType) Match(pattern MediaType) bool {
+	if pattern.Type != "*" && pattern.Type != m.Type {
+		return false
+	}
+
+	switch {
+	case pattern.Subtype == "*":
+	case strings.HasPrefix(pattern.Subtype, "*+"):
+		if m.Suffix != pattern.Suffix {
+			return false
+		}
+	case pattern.Subtype != m.Subtype:
+		return false
+	}
+
+	for k, v := range pattern.Params {
+		if !strings.EqualFold(m.Params[k], v) {
+			return false
+		}
+	}
+	return true
+}
+
+// String returns string representation of current instance.
+func (m MediaType) String() string {
+	return mime.FormatMediaType(m.Type+"/"+m.Subtype, m.Params)
+}
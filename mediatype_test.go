@@ -0,0 +1,118 @@
+/*
+ * Copyright 2016 Fabrício Godoy
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package web
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestParseMediaType(t *testing.T) {
+	mt, err := ParseMediaType("Application/Vnd.Foo+JSON; charset=UTF-8")
+	if err != nil {
+		t.Fatalf("Error parsing media type: %v", err)
+	}
+	if mt.Type != "application" ||
+		mt.Subtype != "vnd.foo+json" ||
+		mt.Suffix != "json" ||
+		mt.Params["charset"] != "UTF-8" {
+		t.Errorf("Unexpected media type: %#v", mt)
+	}
+	if mt.String() != "application/vnd.foo+json; charset=UTF-8" {
+		t.Errorf("Unexpected string representation: %s", mt.String())
+	}
+
+	if _, err := ParseMediaType("application"); err == nil {
+		t.Error("Media type without subtype should return an error")
+	}
+	_, err = NewHeader().Accept().SetValue("application").MediaType()
+	if herr, ok := err.(*HeaderValueError); !ok || herr.Name != "Accept" {
+		t.Errorf("Error should name parsed header: %#v", err)
+	}
+	if _, err := NewHeader().ContentType().Empty().
+		SetValue("/json").
+		MediaType(); err == nil {
+		t.Error("Malformed media type should return an error")
+	}
+}
+
+func TestMediaTypeMatch(t *testing.T) {
+	testValues := []struct {
+		value   string
+		pattern string
+		match   bool
+		json    bool
+	}{
+		{"application/json", "application/json", true, true},
+		{"application/json; charset=utf-8", "application/*", true, true},
+		{"application/problem+json", "application/*+json", true, true},
+		{"application/vnd.foo+json", "*/*", true, true},
+		{"application/xml", "application/*+json", false, false},
+		{"text/json", "application/json", false, false},
+		{"text/html", "text/html; level=1", false, false},
+	}
+
+	for _, v := range testValues {
+		mt, _ := ParseMediaType(v.value)
+		pattern, _ := ParseMediaType(v.pattern)
+		if mt.Match(pattern) != v.match {
+			t.Errorf("Unexpected match of '%s' against '%s'",
+				v.value, v.pattern)
+		}
+		if mt.IsJSON() != v.json {
+			t.Errorf("Unexpected JSON compatibility of '%s'", v.value)
+		}
+	}
+}
+
+func TestJSONReadRequestContentType(t *testing.T) {
+	testValues := []struct {
+		contentType string
+		status      int
+	}{
+		{"", http.StatusOK},
+		{"application/json", http.StatusOK},
+		{"application/vnd.foo+json; charset=utf-8", http.StatusOK},
+		{"application/problem+json", http.StatusOK},
+		{"application/xml", http.StatusUnsupportedMediaType},
+		{"text/plain", http.StatusUnsupportedMediaType},
+		{"garbage/", http.StatusUnsupportedMediaType},
+	}
+
+	for _, v := range testValues {
+		req := httptest.NewRequest("POST", "/",
+			strings.NewReader(`{"Number":40}`))
+		if len(v.contentType) > 0 {
+			NewHeader().ContentType().Empty().
+				SetValue(v.contentType).
+				Write(req.Header)
+		}
+		resp := httptest.NewRecorder()
+
+		var foo Foo
+		ok := JSONReadRequest(req, BodyMaxLength, &foo, resp)
+		if ok != (v.status == http.StatusOK) || resp.Code != v.status {
+			t.Errorf("Unexpected result for '%s': %v (%d)",
+				v.contentType, ok, resp.Code)
+		}
+		if ok && foo.Number != 40 {
+			t.Errorf("Unexpected decoded object: %#v", foo)
+		}
+	}
+}
@@ -0,0 +1,215 @@
+/*
+ * Copyright 2016 Fabrício Godoy
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package web
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+)
+
+const (
+	// JSONErrorCodeTooManyRecords defines the JSONError code when content has
+	// more records than maximum count.
+	JSONErrorCodeTooManyRecords = "too_many_records"
+)
+
+// A NDJSONReader represents options to read newline-delimited JSON content
+// sent by client, one record per line.
+type NDJSONReader struct {
+	// Record defines options to decode each record. Its MaxLength defines the
+	// maximum length of each line, which defaults to MaxLength when zero, and
+	// data after record is always rejected.
+	Record JSONReader
+	// MaxLength defines the maximum length of whole content.
+	MaxLength int64
+	// MaxRecords defines the maximum number of records. Records are not
+	// limited when zero.
+	MaxRecords int
+}
+
+// NewDecoder creates a new NDJSONDecoder that reads records from request
+// body.
+//
+// Returns a *JSONError when request defines a content type which is not
+// newline-delimited JSON (415), or defines a content length larger than
+// maximum length (413).
+func (nr NDJSONReader) NewDecoder(r *http.Request) (*NDJSONDecoder, error) {
+	if jerr := checkNDJSONContentType(r); jerr != nil {
+		return nil, jerr
+	}
+	if r.ContentLength > nr.MaxLength {
+		return nil, tooLargeError(nr.MaxLength)
+	}
+
+	return nr.NewBodyDecoder(r.Body), nil
+}
+
+// NewBodyDecoder creates a new NDJSONDecoder that reads records from client
+// sent content.
+func (nr NDJSONReader) NewBodyDecoder(body io.ReadCloser) *NDJSONDecoder {
+	record := nr.Record
+	record.DisallowTrailingData = true
+	if record.MaxLength == 0 {
+		record.MaxLength = nr.MaxLength
+	}
+	return &NDJSONDecoder{
+		body:       body,
+		r:          bufio.NewReader(&maxLengthReader{body, nr.MaxLength}),
+		record:     record,
+		maxlen:     nr.MaxLength,
+		maxRecords: nr.MaxRecords,
+	}
+}
+
+// A NDJSONDecoder represents a decoder of newline-delimited JSON content that
+// yields one record at a time.
+type NDJSONDecoder struct {
+	body       io.ReadCloser
+	r          *bufio.Reader
+	record     JSONReader
+	maxlen     int64
+	maxRecords int
+	line       int
+	count      int
+	err        error
+}
+
+// Decode reads next record and writes it to object pointed to by obj. Blank
+// lines are skipped. The decoded object is validated when enabled by record
+// options.
+//
+// Returns io.EOF when there are no more records. Returns a *NDJSONLineError
+// when current line is not valid, is rejected or is larger than record
+// maximum length; decoding can continue to next line. Otherwise, returns a
+// *JSONError when content is larger than maximum length (413) or has more
+// records than maximum count (413); it is returned by subsequent calls too.
+func (d *NDJSONDecoder) Decode(obj interface{}) error {
+	if d.err != nil {
+		return d.err
+	}
+
+	for {
+		data, tooLarge, err := d.readLine()
+		if err == errTooLarge {
+			err = tooLargeError(d.maxlen)
+		} else if err != nil && err != io.EOF {
+			err = NewJSONError().FromError(err).Build()
+		}
+		if err != nil {
+			d.err = err
+			return err
+		}
+
+		d.line++
+		if !tooLarge && len(bytes.TrimSpace(data)) == 0 {
+			continue
+		}
+
+		d.count++
+		if d.maxRecords > 0 && d.count > d.maxRecords {
+			d.err = tooManyRecordsError(d.maxRecords)
+			return d.err
+		}
+
+		if tooLarge {
+			return &NDJSONLineError{d.line, tooLargeError(d.record.MaxLength)}
+		}
+		if jerr := d.record.decode(bytes.NewReader(data), obj); jerr != nil {
+			return &NDJSONLineError{d.line, jerr}
+		}
+		if d.record.Validate {
+			if fields := Validate(obj); len(fields) > 0 {
+				return &NDJSONLineError{d.line, validationError(fields)}
+			}
+		}
+		return nil
+	}
+}
+
+// Line returns the number of the last read line, starting at one.
+func (d *NDJSONDecoder) Line() int {
+	return d.line
+}
+
+// Close drains body, up to a bound, and closes it.
+func (d *NDJSONDecoder) Close() error {
+	io.CopyN(ioutil.Discard, d.body, maxDrainLength)
+	return d.body.Close()
+}
+
+// readLine reads next line without line terminator. A line larger than record
+// maximum length is discarded and reported by tooLarge.
+func (d *NDJSONDecoder) readLine() (line []byte, tooLarge bool, err error) {
+	read := false
+	for {
+		chunk, err := d.r.ReadSlice('\n')
+		read = read || len(chunk) > 0
+		if !tooLarge {
+			line = append(line, chunk...)
+			if int64(len(bytes.TrimRight(line, "\r\n"))) > d.record.MaxLength {
+				tooLarge = true
+				line = nil
+			}
+		}
+
+		switch {
+		case err == bufio.ErrBufferFull:
+			continue
+		case err == io.EOF && read:
+		case err != nil:
+			return nil, false, err
+		}
+
+		return bytes.TrimRight(line, "\r\n"), tooLarge, nil
+	}
+}
+
+// checkNDJSONContentType returns a JSONError when specified request defines a
+// content type which is not newline-delimited JSON.
+func checkNDJSONContentType(r *http.Request) *JSONError {
+	ct := NewHeader().ContentType().Empty().Read(r.Header)
+	if len(ct.Value) == 0 {
+		return nil
+	}
+
+	mt, err := ct.MediaType()
+	if err == nil && mt.Type == "application" {
+		switch mt.Subtype {
+		case "x-ndjson", "ndjson", "jsonl", "x-jsonlines":
+			return nil
+		}
+	}
+
+	return NewJSONError().
+		Status(http.StatusUnsupportedMediaType).
+		Message(fmt.Sprintf(
+			"The content type '%s' is not supported, expected NDJSON",
+			ct.Value)).
+		Build()
+}
+
+func tooManyRecordsError(max int) *JSONError {
+	return NewJSONError().
+		CustomError(JSONErrorCodeTooManyRecords, "DecodeError",
+			fmt.Sprintf("The content has more than %d records", max)).
+		Status(http.StatusRequestEntityTooLarge).
+		Build()
+}
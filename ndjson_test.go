@@ -0,0 +1,186 @@
+/*
+ * Copyright 2016 Fabrício Godoy
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package web
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+type NDRecord struct {
+	Name string `json:"name" validate:"required"`
+}
+
+func TestNDJSONDecoder(t *testing.T) {
+	content := strings.Join([]string{
+		`{"name":"a"}`,
+		``,
+		`{"name":`,
+		`{"name":"` + strings.Repeat("x", 40) + `"}`,
+		`{"name":""}`,
+		`{"name":"b"} {"name":"c"}`,
+		`{"name":"d"}` + "\r",
+		`{"name":"e"}`,
+	}, "\n")
+	expected := []struct {
+		name   string
+		line   int
+		status int
+		code   string
+	}{
+		{"a", 1, 0, ""},
+		{"", 3, StatusUnprocessableEntity, JSONErrorCodeMalformed},
+		{"", 4, http.StatusRequestEntityTooLarge, JSONErrorCodeTooLarge},
+		{"", 5, StatusUnprocessableEntity, ""},
+		{"", 6, StatusUnprocessableEntity, JSONErrorCodeTrailingData},
+		{"d", 7, 0, ""},
+		{"e", 8, 0, ""},
+	}
+
+	body := &countingReader{Reader: strings.NewReader(content)}
+	dec := NDJSONReader{
+		Record:    JSONReader{MaxLength: 32, Validate: true},
+		MaxLength: 1024,
+	}.NewBodyDecoder(body)
+
+	for _, v := range expected {
+		var rec NDRecord
+		err := dec.Decode(&rec)
+		if dec.Line() != v.line {
+			t.Errorf("Expected line %d but got %d", v.line, dec.Line())
+		}
+		if v.status == 0 {
+			if err != nil || rec.Name != v.name {
+				t.Errorf("Unexpected record on line %d: %#v (%v)",
+					v.line, rec, err)
+			}
+			continue
+		}
+
+		lerr, ok := err.(*NDJSONLineError)
+		if !ok {
+			t.Fatalf("Expected NDJSONLineError on line %d but got %#v",
+				v.line, err)
+		}
+		if lerr.Line != v.line ||
+			lerr.Err.Status != v.status ||
+			(len(v.code) > 0 && lerr.Err.Code != v.code) {
+			t.Errorf("Unexpected error on line %d: %#v", v.line, lerr.Err)
+		}
+	}
+
+	var rec NDRecord
+	if err := dec.Decode(&rec); err != io.EOF {
+		t.Errorf("Expected end of content but got %v", err)
+	}
+	if err := dec.Close(); err != nil || body.closes != 1 {
+		t.Errorf("Body should be closed once: %d closes (%v)",
+			body.closes, err)
+	}
+}
+
+func TestNDJSONDecoderLimits(t *testing.T) {
+	testValues := []struct {
+		reader  NDJSONReader
+		records int
+		code    string
+	}{
+		{NDJSONReader{JSONReader{MaxLength: 32}, 40, 0},
+			2, JSONErrorCodeTooLarge},
+		{NDJSONReader{JSONReader{MaxLength: 32}, 1024, 3},
+			3, JSONErrorCodeTooManyRecords},
+	}
+
+	content := strings.Repeat(`{"name":"abc"}`+"\n", 5)
+	for _, v := range testValues {
+		dec := v.reader.NewBodyDecoder(
+			&countingReader{Reader: strings.NewReader(content)})
+
+		var err error
+		records := 0
+		for err == nil {
+			var rec NDRecord
+			if err = dec.Decode(&rec); err == nil {
+				records++
+			}
+		}
+
+		if records != v.records {
+			t.Errorf("Expected %d records but got %d", v.records, records)
+		}
+		jerr, ok := err.(*JSONError)
+		if !ok || jerr.Status != http.StatusRequestEntityTooLarge ||
+			jerr.Code != v.code {
+			t.Errorf("Unexpected error: %#v", err)
+		}
+
+		var rec NDRecord
+		if dec.Decode(&rec) != err {
+			t.Error("Error should be returned by subsequent calls")
+		}
+	}
+}
+
+func TestNDJSONDecoderDefaultRecordLength(t *testing.T) {
+	dec := NDJSONReader{MaxLength: 1 << 20}.NewBodyDecoder(
+		&countingReader{Reader: strings.NewReader(`{"name":"a"}`)})
+
+	var rec NDRecord
+	if err := dec.Decode(&rec); err != nil || rec.Name != "a" {
+		t.Errorf("Unexpected record: %#v (%v)", rec, err)
+	}
+}
+
+func TestNDJSONReaderNewDecoder(t *testing.T) {
+	testValues := []struct {
+		contentType string
+		length      int64
+		status      int
+	}{
+		{"", -1, 0},
+		{"application/x-ndjson", -1, 0},
+		{"application/jsonl; charset=utf-8", 10, 0},
+		{"application/json", -1, http.StatusUnsupportedMediaType},
+		{"application/x-ndjson", 2048, http.StatusRequestEntityTooLarge},
+	}
+
+	nr := NDJSONReader{Record: JSONReader{MaxLength: 32}, MaxLength: 1024}
+	for _, v := range testValues {
+		req := httptest.NewRequest("POST", "/",
+			strings.NewReader(`{"name":"a"}`))
+		req.ContentLength = v.length
+		NewHeader().ContentType().Empty().
+			SetValue(v.contentType).
+			Write(req.Header)
+
+		dec, err := nr.NewDecoder(req)
+		if v.status == 0 {
+			if err != nil {
+				t.Errorf("Unexpected error for '%s': %v", v.contentType, err)
+			}
+			continue
+		}
+
+		jerr, ok := err.(*JSONError)
+		if dec != nil || !ok || jerr.Status != v.status {
+			t.Errorf("Unexpected result for '%s': %#v", v.contentType, err)
+		}
+	}
+}
@@ -0,0 +1,188 @@
+/*
+ * Copyright 2016 Fabrício Godoy
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package web
+
+import (
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	mediaTypeJSON = "application/json"
+	mediaTypeText = "text/plain"
+	mediaTypeXML  = "application/xml"
+)
+
+// A AcceptRange represents a media range of Accept HTTP header, as defined by
+// RFC 7231. Its type and subtype can be asterisks, and its parameters does not
+// include quality.
+type AcceptRange struct {
+	MediaType
+	// Relative quality factor, between zero and one.
+	Quality float64
+}
+
+// ParseAccept parses specified Accept HTTP header value and returns its media
+// ranges sorted by preference. Malformed ranges are ignored.
+func ParseAccept(value string) []AcceptRange {
+	result := make([]AcceptRange, 0)
+	for _, v := range strings.Split(value, ",") {
+		v = strings.TrimSpace(v)
+		if len(v) == 0 {
+			continue
+		}
+
+		mt, err := parseMediaType(headerNameAccept, v)
+		if err != nil {
+			continue
+		}
+
+		ar := AcceptRange{mt, 1}
+		if ar.Type == "*" && ar.Subtype != "*" {
+			continue
+		}
+		if q, ok := ar.Params["q"]; ok {
+			qv, err := strconv.ParseFloat(q, 64)
+			if err != nil || qv < 0 || qv > 1 {
+				continue
+			}
+			ar.Quality = qv
+			delete(ar.Params, "q")
+		}
+
+		result = append(result, ar)
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		if result[i].Quality != result[j].Quality {
+			return result[i].Quality > result[j].Quality
+		}
+		return result[i].specificity() > result[j].specificity()
+	})
+	return result
+}
+
+// Match returns whether specified media type matches current media range.
+func (ar AcceptRange) Match(mediaType string) bool {
+	mt, err := ParseMediaType(mediaType)
+	if err != nil {
+		return false
+	}
+	return mt.Match(ar.MediaType)
+}
+
+func (ar AcceptRange) specificity() int {
+	switch {
+	case ar.Type == "*":
+		return 0
+	case ar.Subtype == "*":
+		return 1
+	default:
+		return 2 + len(ar.Params)
+	}
+}
+
+// Negotiate returns the offered media type which best matches the Accept HTTP
+// header of specified request. The first offer is returned when request does
+// not define acceptable types, or every defined type is malformed.
+//
+// Returns an empty string when no offer is acceptable.
+func Negotiate(r *http.Request, offers ...string) string {
+	ranges := ParseAccept(NewHeader().Accept().ReadAll(r.Header).Value)
+	if len(ranges) == 0 {
+		if len(offers) == 0 {
+			return ""
+		}
+		return offers[0]
+	}
+
+	best, bestQ := "", 0.0
+	for _, offer := range offers {
+		if q := offerQuality(ranges, offer); q > bestQ {
+			best, bestQ = offer, q
+		}
+	}
+
+	return best
+}
+
+// offerQuality returns the quality of specified media type as defined by the
+// most specific matching media range.
+func offerQuality(ranges []AcceptRange, offer string) float64 {
+	q, spec := 0.0, -1
+	for _, ar := range ranges {
+		if ar.specificity() > spec && ar.Match(offer) {
+			q, spec = ar.Quality, ar.specificity()
+		}
+	}
+	return q
+}
+
+// Write serializes defined content to the format which best matches the
+// Accept HTTP header of specified request, among JSON, XML and plain text.
+// Content is serialized before anything is written, as JSONWriteBuffered
+// does, and the response varies by Accept HTTP header.
+//
+// A Not Acceptable (406) JSONError is written when no format is acceptable.
+func Write(
+	w http.ResponseWriter,
+	r *http.Request,
+	status int,
+	content interface{},
+) error {
+	NewHeader().Vary().SetList("Accept").Append(w.Header())
+	switch Negotiate(r, mediaTypeJSON, mediaTypeXML, mediaTypeText) {
+	case mediaTypeJSON:
+		return JSONWriteBuffered(w, status, content)
+	case mediaTypeXML:
+		var data []byte
+		if content != nil {
+			var err error
+			if data, err = xml.Marshal(content); err != nil {
+				writeError(w, NewJSONError().FromError(err).Build())
+				return err
+			}
+			data = append(data, '\n')
+		}
+
+		NewHeader().ContentType().XML().Write(w.Header())
+		NewHeader().ContentLength().SetInt(int64(len(data))).Write(w.Header())
+		w.WriteHeader(status)
+		_, err := w.Write(data)
+		return err
+	case mediaTypeText:
+		NewHeader().ContentType().Text().Write(w.Header())
+		w.WriteHeader(status)
+		if content == nil {
+			return nil
+		}
+		_, err := fmt.Fprintln(w, content)
+		return err
+	}
+
+	jerr := NewJSONError().
+		Status(http.StatusNotAcceptable).
+		Message(fmt.Sprintf("None of available content types is acceptable: %s",
+			strings.Join(
+				[]string{mediaTypeJSON, mediaTypeXML, mediaTypeText}, ", "))).
+		Build()
+	return writeError(w, jerr)
+}
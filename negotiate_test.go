@@ -0,0 +1,145 @@
+/*
+ * Copyright 2016 Fabrício Godoy
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package web
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestParseAccept(t *testing.T) {
+	ranges := ParseAccept(
+		"text/*;q=0.3, text/html;q=0.7, text/html;level=1, */*;q=0.5, bad;q=x")
+
+	expected := []struct {
+		mediaType string
+		quality   float64
+	}{
+		{"text/html", 1},
+		{"text/html", 0.7},
+		{"*/*", 0.5},
+		{"text/*", 0.3},
+	}
+	if len(ranges) != len(expected) {
+		t.Fatalf("Unexpected media ranges: %v", ranges)
+	}
+	for i, v := range expected {
+		if ranges[i].Type+"/"+ranges[i].Subtype != v.mediaType ||
+			ranges[i].Quality != v.quality {
+			t.Errorf("Unexpected media range #%d: %v", i, ranges[i])
+		}
+	}
+	if ranges[0].Params["level"] != "1" {
+		t.Errorf("Media range parameters was not parsed: %v", ranges[0])
+	}
+}
+
+func TestNegotiate(t *testing.T) {
+	offers := []string{"application/json", "application/xml", "text/plain"}
+	testValues := []struct {
+		accept   string
+		expected string
+	}{
+		{"", "application/json"},
+		{"*/*", "application/json"},
+		{"application/xml", "application/xml"},
+		{"text/*, application/json;q=0.5", "text/plain"},
+		{"application/*;q=0.8, application/json;q=0.1", "application/xml"},
+		{"*/*, application/json;q=0", "application/xml"},
+		{"image/png", ""},
+		{"foo, */plain, text/html;q=2", "application/json"},
+	}
+
+	for _, v := range testValues {
+		req := httptest.NewRequest("GET", "/", nil)
+		if len(v.accept) > 0 {
+			NewHeader().Accept().SetValue(v.accept).Write(req.Header)
+		}
+
+		if result := Negotiate(req, offers...); result != v.expected {
+			t.Errorf("Unexpected negotiation for '%s': '%s' instead of '%s'",
+				v.accept, result, v.expected)
+		}
+	}
+}
+
+func TestNegotiatedWrite(t *testing.T) {
+	foo := Foo{Number: 40, Text: "Lorem ipsum"}
+	testValues := []struct {
+		accept      string
+		status      int
+		contentType string
+	}{
+		{"application/json", http.StatusOK, "application/json"},
+		{"application/xml", http.StatusOK, "application/xml"},
+		{"text/plain", http.StatusOK, "text/plain"},
+		{"image/png", http.StatusNotAcceptable, "application/json"},
+	}
+
+	for _, v := range testValues {
+		req := httptest.NewRequest("GET", "/", nil)
+		NewHeader().Accept().SetValue(v.accept).Write(req.Header)
+		resp := httptest.NewRecorder()
+		Write(resp, req, http.StatusOK, foo)
+
+		if resp.Code != v.status {
+			t.Errorf("Unexpected status for '%s': %d", v.accept, resp.Code)
+		}
+		if ct := resp.Header().Get("Content-Type"); !strings.HasPrefix(
+			ct, v.contentType) {
+			t.Errorf("Unexpected content type for '%s': %s", v.accept, ct)
+		}
+		if vary := resp.Header().Get("Vary"); vary != "Accept" {
+			t.Errorf("Unexpected Vary header for '%s': %s", v.accept, vary)
+		}
+
+		var fooCopy Foo
+		switch v.accept {
+		case "application/json":
+			if err := json.NewDecoder(resp.Body).Decode(&fooCopy); err != nil ||
+				!foo.IsEqual(fooCopy) {
+				t.Errorf("Unexpected JSON content: %s", resp.Body.String())
+			}
+		case "application/xml":
+			if err := xml.NewDecoder(resp.Body).Decode(&fooCopy); err != nil ||
+				!foo.IsEqual(fooCopy) {
+				t.Errorf("Unexpected XML content: %s", resp.Body.String())
+			}
+		}
+	}
+}
+
+func TestNegotiatedWriteXMLError(t *testing.T) {
+	req := httptest.NewRequest("GET", "/", nil)
+	NewHeader().Accept().SetValue("application/xml").Write(req.Header)
+	resp := httptest.NewRecorder()
+
+	if err := Write(resp, req, http.StatusOK, struct{ A int }{1}); err == nil {
+		t.Error("Expected XML encoding error")
+	}
+	if resp.Code != http.StatusInternalServerError {
+		t.Errorf("Unexpected status: %d", resp.Code)
+	}
+	var jerr JSONError
+	if err := json.Unmarshal(resp.Body.Bytes(), &jerr); err != nil {
+		t.Errorf("Unexpected content: %s", resp.Body.String())
+	}
+}
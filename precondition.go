@@ -0,0 +1,114 @@
+/*
+ * Copyright 2016 Fabrício Godoy
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package web
+
+import (
+	"net/http"
+	"time"
+)
+
+// A VersionFunc represents a function that returns the current version of a
+// resource, as its entity tag and last modification time. A zero ETag means
+// the resource does not exist, and a zero time is ignored.
+type VersionFunc func() (ETag, time.Time)
+
+// CheckPrecondition evaluates If-Match and If-Unmodified-Since HTTP headers of
+// specified request against the current version of resource, as returned by
+// current function.
+//
+// Returns true whether request can proceed; otherwise, a Precondition Failed
+// (412) JSONError is written. When required is true and request has no
+// precondition, a Precondition Required (428) JSONError is written. An
+// If-Unmodified-Since header which is not a valid HTTP-date is ignored.
+func CheckPrecondition(
+	w http.ResponseWriter,
+	r *http.Request,
+	required bool,
+	current VersionFunc,
+) bool {
+	ifMatch := NewHeader().IfMatch().ReadAll(r.Header).Value
+	// An invalid HTTP-date is ignored, as defined by RFC 7232
+	since, sinceErr := NewHeader().IfUnmodifiedSince().Read(r.Header).Time()
+	hasSince := sinceErr == nil
+
+	if len(ifMatch) == 0 && !hasSince {
+		if !required {
+			return true
+		}
+
+		jerr := NewJSONError().
+			Status(http.StatusPreconditionRequired).
+			Message("This request must be conditional, " +
+				"an If-Match or If-Unmodified-Since header is required").
+			Build()
+		writeError(w, jerr)
+		return false
+	}
+
+	etag, lastModified := current()
+	if len(ifMatch) > 0 {
+		if matchETag(ifMatch, etag) {
+			return true
+		}
+	} else if lastModified.IsZero() ||
+		!lastModified.Truncate(time.Second).After(since) {
+		return true
+	}
+
+	jerr := NewJSONError().
+		Status(http.StatusPreconditionFailed).
+		Message("The resource was modified, " +
+			"retrieve its current version and retry").
+		Build()
+	writeError(w, jerr)
+	return false
+}
+
+// JSONReadPrecondition checks request preconditions, as CheckPrecondition
+// does, and then tries to read request body, as JSONReadRequest does.
+func JSONReadPrecondition(
+	r *http.Request,
+	maxlen int64,
+	obj interface{},
+	w http.ResponseWriter,
+	required bool,
+	current VersionFunc,
+) bool {
+	if !CheckPrecondition(w, r, required, current) {
+		return false
+	}
+
+	return JSONReadRequest(r, maxlen, obj, w)
+}
+
+// matchETag returns whether specified If-Match value matches the entity tag
+// using strong comparison.
+func matchETag(ifMatch string, etag ETag) bool {
+	tags, any := ParseETags(ifMatch)
+	if len(etag.Tag) == 0 {
+		return false
+	}
+	if any {
+		return true
+	}
+	for _, v := range tags {
+		if v.StrongMatch(etag) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,129 @@
+/*
+ * Copyright 2016 Fabrício Godoy
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package web
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCheckPrecondition(t *testing.T) {
+	modified := time.Date(2016, 3, 1, 10, 0, 0, 0, time.UTC)
+	current := func() (ETag, time.Time) {
+		return ETag{"v2", false}, modified
+	}
+	missing := func() (ETag, time.Time) {
+		return ETag{}, time.Time{}
+	}
+
+	testValues := []struct {
+		ifMatch      string
+		ifUnmodified time.Time
+		required     bool
+		version      VersionFunc
+		status       int
+	}{
+		{"", time.Time{}, false, current, http.StatusOK},
+		{"", time.Time{}, true, current, http.StatusPreconditionRequired},
+		{`"v2"`, time.Time{}, true, current, http.StatusOK},
+		{`"v1", "v2"`, time.Time{}, true, current, http.StatusOK},
+		{`"v1"`, time.Time{}, true, current, http.StatusPreconditionFailed},
+		{`W/"v2"`, time.Time{}, true, current, http.StatusPreconditionFailed},
+		{"*", time.Time{}, true, current, http.StatusOK},
+		{"*", time.Time{}, true, missing, http.StatusPreconditionFailed},
+		{"", modified, true, current, http.StatusOK},
+		{"", modified.Add(-time.Second), true, current,
+			http.StatusPreconditionFailed},
+	}
+
+	for i, v := range testValues {
+		req := httptest.NewRequest("PUT", "/", nil)
+		if len(v.ifMatch) > 0 {
+			NewHeader().IfMatch().SetValue(v.ifMatch).Write(req.Header)
+		}
+		if !v.ifUnmodified.IsZero() {
+			NewHeader().IfUnmodifiedSince().
+				SetTime(v.ifUnmodified).
+				Write(req.Header)
+		}
+		resp := httptest.NewRecorder()
+
+		ok := CheckPrecondition(resp, req, v.required, v.version)
+		if ok != (v.status == http.StatusOK) || resp.Code != v.status {
+			t.Errorf("Unexpected result on test #%d: %v (%d)",
+				i, ok, resp.Code)
+		}
+	}
+}
+
+func TestCheckPreconditionInvalidDate(t *testing.T) {
+	current := func() (ETag, time.Time) {
+		return ETag{"v2", false}, time.Now()
+	}
+
+	testValues := []struct {
+		required bool
+		status   int
+	}{
+		{false, http.StatusOK},
+		{true, http.StatusPreconditionRequired},
+	}
+
+	for _, v := range testValues {
+		req := httptest.NewRequest("PUT", "/", nil)
+		NewHeader().IfUnmodifiedSince().SetValue("garbage").Write(req.Header)
+		resp := httptest.NewRecorder()
+
+		ok := CheckPrecondition(resp, req, v.required, current)
+		if ok != (v.status == http.StatusOK) || resp.Code != v.status {
+			t.Errorf("Unexpected result when required is %v: %v %d",
+				v.required, ok, resp.Code)
+		}
+	}
+}
+
+func TestJSONReadPrecondition(t *testing.T) {
+	current := func() (ETag, time.Time) {
+		return ETag{"v2", false}, time.Time{}
+	}
+
+	req := httptest.NewRequest("PUT", "/", strings.NewReader(`{"Number":40}`))
+	NewHeader().IfMatch().SetValue(`"v2"`).Write(req.Header)
+	resp := httptest.NewRecorder()
+
+	var foo Foo
+	if !JSONReadPrecondition(req, BodyMaxLength, &foo, resp, true, current) {
+		t.Fatalf("Request should be read: %s", resp.Body.String())
+	}
+	if foo.Number != 40 {
+		t.Errorf("Unexpected decoded object: %#v", foo)
+	}
+
+	req = httptest.NewRequest("PUT", "/", strings.NewReader(`{"Number":40}`))
+	NewHeader().IfMatch().SetValue(`"v1"`).Write(req.Header)
+	resp = httptest.NewRecorder()
+	foo = Foo{}
+	if JSONReadPrecondition(req, BodyMaxLength, &foo, resp, true, current) {
+		t.Error("Request with outdated version should not be read")
+	}
+	if foo.Number != 0 {
+		t.Errorf("Object should not be decoded: %#v", foo)
+	}
+}
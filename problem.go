@@ -0,0 +1,197 @@
+/*
+ * Copyright 2016 Fabrício Godoy
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package web
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+const (
+	// ProblemTypeBlank defines the default problem type, which means that
+	// problem has no additional semantics beyond HTTP status.
+	ProblemTypeBlank = "about:blank"
+
+	problemExtCode      = "code"
+	problemExtErrorType = "errorType"
+	problemExtFields    = "fields"
+	problemExtRequestID = "requestId"
+)
+
+// A ProblemDetails represents an error returned by HTTP API, as defined by
+// RFC 7807.
+type ProblemDetails struct {
+	// A URI reference that identifies the problem type.
+	Type string
+	// A short summary of the problem type.
+	Title string
+	// HTTP status code.
+	Status int
+	// An explanation specific to this occurrence of the problem.
+	Detail string
+	// A URI reference that identifies this occurrence of the problem.
+	Instance string
+	// Additional members of the problem.
+	Extensions map[string]interface{}
+}
+
+// ProblemDetails converts current instance to ProblemDetails. The reference
+// URL defines the problem type, and code, type, request identifier and fields
+// are kept as extension members.
+func (e *JSONError) ProblemDetails() *ProblemDetails {
+	p := &ProblemDetails{
+		Type:       e.MoreInfo,
+		Title:      http.StatusText(e.Status),
+		Status:     e.Status,
+		Detail:     e.Message,
+		Extensions: make(map[string]interface{}),
+	}
+	if len(p.Type) == 0 {
+		p.Type = ProblemTypeBlank
+	}
+	if len(e.Code) > 0 {
+		p.Extensions[problemExtCode] = e.Code
+	}
+	if len(e.Type) > 0 {
+		p.Extensions[problemExtErrorType] = e.Type
+	}
+	if len(e.RequestID) > 0 {
+		p.Extensions[problemExtRequestID] = e.RequestID
+	}
+	if len(e.Fields) > 0 {
+		p.Extensions[problemExtFields] = e.Fields
+	}
+
+	return p
+}
+
+// Error returns string representation of current instance error.
+func (p *ProblemDetails) Error() string {
+	return p.JSONError().Error()
+}
+
+// JSONError converts current instance to JSONError. Extension members other
+// than code, type, request identifier and fields are discarded.
+func (p *ProblemDetails) JSONError() *JSONError {
+	e := &JSONError{
+		Status:  p.Status,
+		Message: p.Detail,
+	}
+	if p.Type != ProblemTypeBlank {
+		e.MoreInfo = p.Type
+	}
+	if len(e.Message) == 0 {
+		e.Message = p.Title
+	}
+	e.Code, _ = p.Extensions[problemExtCode].(string)
+	e.Type, _ = p.Extensions[problemExtErrorType].(string)
+	e.RequestID, _ = p.Extensions[problemExtRequestID].(string)
+	switch fields := p.Extensions[problemExtFields].(type) {
+	case []FieldError:
+		e.Fields = fields
+	case []interface{}:
+		// Decoded from JSON as generic values.
+		data, _ := json.Marshal(fields)
+		json.Unmarshal(data, &e.Fields)
+	}
+
+	return e
+}
+
+// MarshalJSON serializes current instance to JSON, with extension members at
+// the same level of standard members.
+func (p ProblemDetails) MarshalJSON() ([]byte, error) {
+	m := make(map[string]interface{}, len(p.Extensions)+5)
+	for k, v := range p.Extensions {
+		m[k] = v
+	}
+	if len(p.Type) > 0 {
+		m["type"] = p.Type
+	}
+	if len(p.Title) > 0 {
+		m["title"] = p.Title
+	}
+	if p.Status != 0 {
+		m["status"] = p.Status
+	}
+	if len(p.Detail) > 0 {
+		m["detail"] = p.Detail
+	}
+	if len(p.Instance) > 0 {
+		m["instance"] = p.Instance
+	}
+
+	return json.Marshal(m)
+}
+
+// UnmarshalJSON deserializes JSON data to current instance. Unknown members
+// are stored as extension members.
+func (p *ProblemDetails) UnmarshalJSON(data []byte) error {
+	var m map[string]json.RawMessage
+	if err := json.Unmarshal(data, &m); err != nil {
+		return err
+	}
+
+	*p = ProblemDetails{Extensions: make(map[string]interface{})}
+	fields := map[string]interface{}{
+		"type":     &p.Type,
+		"title":    &p.Title,
+		"status":   &p.Status,
+		"detail":   &p.Detail,
+		"instance": &p.Instance,
+	}
+	for k, v := range m {
+		ref, ok := fields[k]
+		if !ok {
+			var ext interface{}
+			if err := json.Unmarshal(v, &ext); err != nil {
+				return err
+			}
+			p.Extensions[k] = ext
+			continue
+		}
+		if err := json.Unmarshal(v, ref); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// An ErrorWriterFunc represents a function that writes specified error to
+// response, using HTTP status defined by error.
+type ErrorWriterFunc func(w http.ResponseWriter, e *JSONError) error
+
+// ErrorWriter defines how errors raised by this package, as rejected requests
+// and recovered panics, are written. Defaults to JSONErrorWrite; ProblemWrite
+// sends errors as Problem Details JSON. It should be defined before serving
+// requests.
+var ErrorWriter ErrorWriterFunc = JSONErrorWrite
+
+// writeError writes specified error using ErrorWriter.
+func writeError(w http.ResponseWriter, e *JSONError) error {
+	if ErrorWriter == nil {
+		return JSONErrorWrite(w, e)
+	}
+	return ErrorWriter(w, e)
+}
+
+// ProblemWrite writes specified error as Problem Details JSON, using HTTP
+// status defined by error.
+func ProblemWrite(w http.ResponseWriter, e *JSONError) error {
+	return JSONWrite(w, e.Status, e.ProblemDetails())
+}
@@ -0,0 +1,194 @@
+/*
+ * Copyright 2016 Fabrício Godoy
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package web
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestProblemDetailsConversion(t *testing.T) {
+	jerr := NewJSONError().
+		CustomError("E42", "ValidationError", "Name is required").
+		Status(http.StatusBadRequest).
+		URL("https://example.com/probs/validation").
+		Build()
+	jerr.RequestID = "foo-42"
+
+	p := jerr.ProblemDetails()
+	if p.Type != jerr.MoreInfo ||
+		p.Title != "Bad Request" ||
+		p.Status != http.StatusBadRequest ||
+		p.Detail != jerr.Message ||
+		p.Extensions["code"] != "E42" ||
+		p.Extensions["requestId"] != "foo-42" {
+		t.Errorf("Unexpected problem details: %#v", p)
+	}
+
+	back := p.JSONError()
+	if !reflect.DeepEqual(back, jerr) {
+		t.Errorf("Round-trip conversion does not match: %#v", back)
+	}
+
+	blank := NewJSONError().Build().ProblemDetails()
+	if blank.Type != ProblemTypeBlank {
+		t.Errorf("Unexpected default problem type: %s", blank.Type)
+	}
+	if blank.JSONError().MoreInfo != "" {
+		t.Error("Default problem type should not be converted to URL")
+	}
+}
+
+func TestProblemDetailsJSON(t *testing.T) {
+	data := []byte(`{"type":"https://example.com/probs/out-of-credit",` +
+		`"title":"You do not have enough credit.","status":403,` +
+		`"detail":"Your current balance is 30, but that costs 50.",` +
+		`"instance":"/account/12345/msgs/abc","balance":30}`)
+
+	var p ProblemDetails
+	if err := json.Unmarshal(data, &p); err != nil {
+		t.Fatalf("Error decoding problem details: %v", err)
+	}
+	if p.Status != http.StatusForbidden ||
+		p.Instance != "/account/12345/msgs/abc" ||
+		p.Extensions["balance"] != float64(30) {
+		t.Errorf("Unexpected problem details: %#v", p)
+	}
+
+	encoded, err := json.Marshal(&p)
+	if err != nil {
+		t.Fatalf("Error encoding problem details: %v", err)
+	}
+	var m map[string]interface{}
+	json.Unmarshal(encoded, &m)
+	if m["balance"] != float64(30) || m["title"] != p.Title {
+		t.Errorf("Extension members should be at top level: %s", encoded)
+	}
+}
+
+func TestProblemWrite(t *testing.T) {
+	resp := httptest.NewRecorder()
+	NewHeader().RequestID().SetValue("foo-42").Write(resp.Header())
+	jerr := NewJSONError().
+		Status(http.StatusNotFound).
+		Message("Not here").
+		Build()
+	ProblemWrite(resp, jerr)
+
+	if resp.Code != http.StatusNotFound {
+		t.Errorf("Unexpected status: %d", resp.Code)
+	}
+	if ct := resp.Header().Get("Content-Type"); ct !=
+		"application/problem+json; charset=utf-8" {
+		t.Errorf("Unexpected content type: %s", ct)
+	}
+
+	var p ProblemDetails
+	if err := json.NewDecoder(resp.Body).Decode(&p); err != nil {
+		t.Fatalf("Error decoding problem details: %v", err)
+	}
+	if p.Detail != "Not here" || p.Extensions["requestId"] != "foo-42" {
+		t.Errorf("Unexpected problem details: %#v", p)
+	}
+}
+
+func TestProblemDetailsFields(t *testing.T) {
+	jerr := NewJSONError().
+		Status(StatusUnprocessableEntity).
+		Field("/name", "required", "Name is required", nil).
+		Build()
+
+	data, err := json.Marshal(jerr.ProblemDetails())
+	if err != nil {
+		t.Fatalf("Error encoding problem details: %v", err)
+	}
+	var p ProblemDetails
+	if err := json.Unmarshal(data, &p); err != nil {
+		t.Fatalf("Error decoding problem details: %v", err)
+	}
+
+	back := p.JSONError()
+	if len(back.Fields) != 1 || back.Fields[0].Pointer != "/name" {
+		t.Errorf("Fields should be kept by conversion: %#v", back.Fields)
+	}
+}
+
+func TestProblemDetailsValueWrite(t *testing.T) {
+	problem := ProblemDetails{
+		Title:      "Not found",
+		Status:     http.StatusNotFound,
+		Extensions: map[string]interface{}{"code": "missing"},
+	}
+
+	resp := httptest.NewRecorder()
+	NewHeader().RequestID().SetValue("rid").Write(resp.Header())
+	if err := JSONWrite(resp, problem.Status, problem); err != nil {
+		t.Fatalf("Error on JSONWrite: %v", err)
+	}
+
+	if ct := resp.Header().Get("Content-Type"); ct !=
+		NewHeader().ContentType().ProblemJSON().Value {
+		t.Errorf("Unexpected content type: %s", ct)
+	}
+	var m map[string]interface{}
+	if err := json.Unmarshal(resp.Body.Bytes(), &m); err != nil {
+		t.Fatalf("Error decoding content: %v", err)
+	}
+	if m["title"] != "Not found" || m["code"] != "missing" ||
+		m["requestId"] != "rid" {
+		t.Errorf("Unexpected content: %s", resp.Body.String())
+	}
+	if _, ok := problem.Extensions["requestId"]; ok {
+		t.Error("Original extensions should not be changed")
+	}
+}
+
+func TestErrorWriter(t *testing.T) {
+	ErrorWriter = ProblemWrite
+	defer func() { ErrorWriter = JSONErrorWrite }()
+
+	resp := httptest.NewRecorder()
+	var foo Foo
+	if JSONRead(nopCloser{strings.NewReader(`{"Number":`)}, 100, &foo, resp) {
+		t.Fatal("Malformed content should not be accepted")
+	}
+
+	if ct := resp.Header().Get("Content-Type"); ct !=
+		NewHeader().ContentType().ProblemJSON().Value {
+		t.Errorf("Unexpected content type: %s", ct)
+	}
+	var problem ProblemDetails
+	if err := json.Unmarshal(resp.Body.Bytes(), &problem); err != nil ||
+		problem.Status != StatusUnprocessableEntity ||
+		problem.Extensions["code"] != JSONErrorCodeMalformed {
+		t.Errorf("Unexpected content: %s", resp.Body.String())
+	}
+
+	resp = httptest.NewRecorder()
+	req := httptest.NewRequest("PUT", "/", nil)
+	CheckPrecondition(resp, req, true, nil)
+	if resp.Code != http.StatusPreconditionRequired ||
+		resp.Header().Get("Content-Type") !=
+			NewHeader().ContentType().ProblemJSON().Value {
+		t.Errorf("Unexpected precondition response: %d %v",
+			resp.Code, resp.Header())
+	}
+}
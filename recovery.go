@@ -0,0 +1,107 @@
+/*
+ * Copyright 2016 Fabrício Godoy
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package web
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"runtime/debug"
+)
+
+// A Logger defines rules for a type that writes log messages.
+//
+// It is implemented by log.Logger.
+type Logger interface {
+	Printf(format string, v ...interface{})
+}
+
+// A stdLogger represents a Logger backed by standard logger.
+type stdLogger struct{}
+
+func (stdLogger) Printf(format string, v ...interface{}) {
+	log.Printf(format, v...)
+}
+
+// A PanicRecovery represents a handler that recovers from panics raised by
+// next HTTP handlers.
+type PanicRecovery struct {
+	// Logger receives panic messages and stack traces. Defaults to standard
+	// logger.
+	Logger Logger
+	// Development defines whether panic message should be sent to client.
+	Development bool
+}
+
+// RecoverHandler is a HTTP request middleware that recovers from panics and
+// writes an Internal Server Error (500) JSONError when response was not
+// started yet, replacing any headers set by next handlers but X-Request-ID.
+// Otherwise, the response is aborted by panicking with
+// http.ErrAbortHandler after panic is logged.
+func (rc PanicRecovery) RecoverHandler(next http.Handler) http.Handler {
+	logger := rc.Logger
+	if logger == nil {
+		logger = stdLogger{}
+	}
+
+	f := func(w http.ResponseWriter, r *http.Request) {
+		capture, cw := CaptureResponse(w)
+		defer func() {
+			v := recover()
+			if v == nil {
+				return
+			}
+			if v == http.ErrAbortHandler {
+				panic(v)
+			}
+
+			logger.Printf("panic serving %s %s: %v\n%s",
+				r.Method, r.URL.Path, v, debug.Stack())
+			if capture.WroteHeader() {
+				// Aborts connection, so client does not take a truncated
+				// response as complete
+				panic(http.ErrAbortHandler)
+			}
+
+			jerr := NewJSONError().
+				Message(http.StatusText(http.StatusInternalServerError))
+			if rc.Development {
+				if err, ok := v.(error); ok {
+					jerr.FromError(err)
+				} else {
+					jerr.Message(fmt.Sprint(v))
+				}
+			}
+			// Headers set by failed handler do not describe the error
+			h := w.Header()
+			id := h.Get(headerNameRequestID)
+			for k := range h {
+				delete(h, k)
+			}
+			if len(id) > 0 {
+				NewHeader().RequestID().SetValue(id).Write(h)
+			}
+
+			built := jerr.Build()
+			writeError(w, built)
+		}()
+
+		next.ServeHTTP(cw, r)
+	}
+
+	return http.HandlerFunc(f)
+}
@@ -0,0 +1,130 @@
+/*
+ * Copyright 2016 Fabrício Godoy
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package web
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestPanicRecovery(t *testing.T) {
+	testValues := []struct {
+		development bool
+		value       interface{}
+		message     string
+	}{
+		{false, "secret failure", http.StatusText(http.StatusInternalServerError)},
+		{true, "secret failure", "secret failure"},
+		{true, errors.New("failed"), "failed"},
+	}
+
+	for _, v := range testValues {
+		var buf bytes.Buffer
+		rc := PanicRecovery{
+			Logger:      log.New(&buf, "", 0),
+			Development: v.development,
+		}
+		value := v.value
+		handler := NewChain(rc.RecoverHandler).ThenFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				panic(value)
+			})
+
+		resp := httptest.NewRecorder()
+		handler.ServeHTTP(resp, httptest.NewRequest("GET", "/", nil))
+
+		if resp.Code != http.StatusInternalServerError {
+			t.Errorf("Unexpected status code: %d", resp.Code)
+		}
+		var jerr JSONError
+		if err := json.NewDecoder(resp.Body).Decode(&jerr); err != nil {
+			t.Fatalf("Error decoding JSONError: %v", err)
+		}
+		if jerr.Message != v.message {
+			t.Errorf("Unexpected error message: '%s' instead of '%s'",
+				jerr.Message, v.message)
+		}
+		if !strings.Contains(buf.String(), "recovery_test.go") {
+			t.Errorf("Stack trace was not logged: %s", buf.String())
+		}
+	}
+}
+
+func TestPanicRecoveryAfterHeaders(t *testing.T) {
+	rc := PanicRecovery{Logger: log.New(&bytes.Buffer{}, "", 0)}
+	handler := NewChain(rc.RecoverHandler).ThenFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusAccepted)
+			w.Write([]byte("partial"))
+			panic("failure")
+		})
+
+	resp := httptest.NewRecorder()
+	func() {
+		defer func() {
+			if v := recover(); v != http.ErrAbortHandler {
+				t.Errorf("Response should be aborted: %v", v)
+			}
+		}()
+		handler.ServeHTTP(resp, httptest.NewRequest("GET", "/", nil))
+	}()
+
+	if resp.Code != http.StatusAccepted {
+		t.Errorf("Status code should not be changed: %d", resp.Code)
+	}
+	if resp.Body.String() != "partial" {
+		t.Errorf("Body should not be changed: %s", resp.Body.String())
+	}
+}
+
+func TestPanicRecoveryClearsHeaders(t *testing.T) {
+	rc := PanicRecovery{Logger: log.New(&bytes.Buffer{}, "", 0)}
+	handler := NewChain(rc.RecoverHandler).ThenFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			NewHeader().ContentLength().SetInt(1000).Write(w.Header())
+			NewHeader().ETag().SetValue(`"v1"`).Write(w.Header())
+			panic("failure")
+		})
+
+	resp := httptest.NewRecorder()
+	NewHeader().RequestID().SetValue("rid").Write(resp.Header())
+	handler.ServeHTTP(resp, httptest.NewRequest("GET", "/", nil))
+
+	if resp.Code != http.StatusInternalServerError {
+		t.Errorf("Unexpected status: %d", resp.Code)
+	}
+	for _, name := range []string{"Content-Length", "ETag"} {
+		if v := resp.Header().Get(name); len(v) > 0 {
+			t.Errorf("Header %s should be cleared: %s", name, v)
+		}
+	}
+	if v := resp.Header().Get("X-Request-ID"); v != "rid" {
+		t.Errorf("Request identifier should be kept: %s", v)
+	}
+
+	var jerr JSONError
+	if err := json.NewDecoder(resp.Body).Decode(&jerr); err != nil ||
+		jerr.RequestID != "rid" {
+		t.Errorf("Unexpected content: %s", resp.Body.String())
+	}
+}
@@ -0,0 +1,101 @@
+/*
+ * Copyright 2016 Fabrício Godoy
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package web
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+)
+
+const (
+	// DefaultRequestIDMaxLength defines the maximum length of an inbound
+	// request identifier when not defined by RequestIdentifier.
+	DefaultRequestIDMaxLength = 128
+)
+
+// A RequestIdentifier represents a handler that identifies each HTTP request.
+type RequestIdentifier struct {
+	// MaxLength defines the maximum length of an inbound identifier. Defaults
+	// to DefaultRequestIDMaxLength.
+	MaxLength int
+	// Generate creates a new identifier. Defaults to 16 random bytes encoded
+	// as hexadecimal.
+	Generate func() string
+}
+
+// IDHandler is a HTTP request middleware that accepts a valid inbound request
+// identifier or generates a new one, stores it into request context and sends
+// it on response headers.
+func (ri RequestIdentifier) IDHandler(next http.Handler) http.Handler {
+	maxlen := ri.MaxLength
+	if maxlen <= 0 {
+		maxlen = DefaultRequestIDMaxLength
+	}
+	generate := ri.Generate
+	if generate == nil {
+		generate = newRequestID
+	}
+
+	f := func(w http.ResponseWriter, r *http.Request) {
+		id := NewHeader().RequestID().Read(r.Header).Value
+		if !isValidRequestID(id, maxlen) {
+			id = generate()
+		}
+
+		NewHeader().RequestID().SetValue(id).Write(w.Header())
+		ctx := context.WithValue(r.Context(), requestIDKey, id)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	}
+
+	return http.HandlerFunc(f)
+}
+
+// RequestID returns the identifier of specified request, or an empty string
+// when request was not identified by a RequestIdentifier.
+func RequestID(r *http.Request) string {
+	id, _ := r.Context().Value(requestIDKey).(string)
+	return id
+}
+
+func isValidRequestID(id string, maxlen int) bool {
+	if len(id) == 0 || len(id) > maxlen {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		c := id[i]
+		switch {
+		case c >= 'a' && c <= 'z',
+			c >= 'A' && c <= 'Z',
+			c >= '0' && c <= '9',
+			c == '-', c == '_', c == '.', c == ':', c == '+', c == '/',
+			c == '=':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+func newRequestID() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		panic(err)
+	}
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,91 @@
+/*
+ * Copyright 2016 Fabrício Godoy
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package web
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestRequestID(t *testing.T) {
+	testValues := []struct {
+		inbound string
+		keep    bool
+	}{
+		{"", false},
+		{"ab-1_D.:", true},
+		{"a b", false},
+		{"<script>", false},
+		{strings.Repeat("a", 8), true},
+		{strings.Repeat("a", 9), false},
+	}
+
+	ri := RequestIdentifier{MaxLength: 8}
+	for _, v := range testValues {
+		var ctxID string
+		handler := NewChain(ri.IDHandler).ThenFunc(
+			func(w http.ResponseWriter, r *http.Request) {
+				ctxID = RequestID(r)
+			})
+
+		req := httptest.NewRequest("GET", "/", nil)
+		if len(v.inbound) > 0 {
+			NewHeader().RequestID().SetValue(v.inbound).Write(req.Header)
+		}
+		resp := httptest.NewRecorder()
+		handler.ServeHTTP(resp, req)
+
+		respID := resp.Header().Get("X-Request-ID")
+		if len(respID) == 0 {
+			t.Errorf("Request ID was not sent for '%s'", v.inbound)
+		}
+		if respID != ctxID {
+			t.Errorf("Response ID '%s' does not match context ID '%s'",
+				respID, ctxID)
+		}
+		if v.keep != (respID == v.inbound) {
+			t.Errorf("Unexpected request ID for '%s': %s", v.inbound, respID)
+		}
+	}
+}
+
+func TestRequestIDOnJSONError(t *testing.T) {
+	jerr := NewJSONError().Message("failure").Build()
+	handler := NewChain(RequestIdentifier{}.IDHandler).ThenFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			JSONWrite(w, jerr.Status, jerr)
+		})
+
+	req := httptest.NewRequest("GET", "/", nil)
+	NewHeader().RequestID().SetValue("foo-42").Write(req.Header)
+	resp := httptest.NewRecorder()
+	handler.ServeHTTP(resp, req)
+
+	var result JSONError
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		t.Fatalf("Error decoding JSONError: %v", err)
+	}
+	if result.RequestID != "foo-42" {
+		t.Errorf("Unexpected request ID on JSONError: '%s'", result.RequestID)
+	}
+	if len(jerr.RequestID) > 0 {
+		t.Error("Original JSONError should not be modified")
+	}
+}
@@ -0,0 +1,233 @@
+/*
+ * Copyright 2016 Fabrício Godoy
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package web
+
+import (
+	"bufio"
+	"io"
+	"net"
+	"net/http"
+)
+
+const (
+	flusherBit = 1 << iota
+	hijackerBit
+	pusherBit
+	readerFromBit
+)
+
+// A ResponseCapture represents the HTTP status and the number of bytes written
+// by next HTTP handlers.
+type ResponseCapture struct {
+	status      int
+	size        int64
+	wroteHeader bool
+}
+
+// CaptureResponse creates a new ResponseCapture for specified ResponseWriter.
+//
+// Returns the new ResponseCapture and a ResponseWriter which should be passed
+// to next HTTP handlers. The returned ResponseWriter implements http.Flusher,
+// http.Hijacker, http.Pusher and io.ReaderFrom only when w implements them.
+func CaptureResponse(
+	w http.ResponseWriter,
+) (*ResponseCapture, http.ResponseWriter) {
+	c := &ResponseCapture{}
+	cw := &captureWriter{w, c}
+
+	mask := 0
+	if _, ok := w.(http.Flusher); ok {
+		mask |= flusherBit
+	}
+	if _, ok := w.(http.Hijacker); ok {
+		mask |= hijackerBit
+	}
+	if _, ok := w.(http.Pusher); ok {
+		mask |= pusherBit
+	}
+	if _, ok := w.(io.ReaderFrom); ok {
+		mask |= readerFromBit
+	}
+
+	switch mask {
+	case 0:
+		return c, struct {
+			http.ResponseWriter
+		}{cw}
+	case flusherBit:
+		return c, struct {
+			http.ResponseWriter
+			http.Flusher
+		}{cw, cw}
+	case hijackerBit:
+		return c, struct {
+			http.ResponseWriter
+			http.Hijacker
+		}{cw, cw}
+	case flusherBit | hijackerBit:
+		return c, struct {
+			http.ResponseWriter
+			http.Flusher
+			http.Hijacker
+		}{cw, cw, cw}
+	case pusherBit:
+		return c, struct {
+			http.ResponseWriter
+			http.Pusher
+		}{cw, cw}
+	case flusherBit | pusherBit:
+		return c, struct {
+			http.ResponseWriter
+			http.Flusher
+			http.Pusher
+		}{cw, cw, cw}
+	case hijackerBit | pusherBit:
+		return c, struct {
+			http.ResponseWriter
+			http.Hijacker
+			http.Pusher
+		}{cw, cw, cw}
+	case flusherBit | hijackerBit | pusherBit:
+		return c, struct {
+			http.ResponseWriter
+			http.Flusher
+			http.Hijacker
+			http.Pusher
+		}{cw, cw, cw, cw}
+	case readerFromBit:
+		return c, struct {
+			http.ResponseWriter
+			io.ReaderFrom
+		}{cw, cw}
+	case flusherBit | readerFromBit:
+		return c, struct {
+			http.ResponseWriter
+			http.Flusher
+			io.ReaderFrom
+		}{cw, cw, cw}
+	case hijackerBit | readerFromBit:
+		return c, struct {
+			http.ResponseWriter
+			http.Hijacker
+			io.ReaderFrom
+		}{cw, cw, cw}
+	case flusherBit | hijackerBit | readerFromBit:
+		return c, struct {
+			http.ResponseWriter
+			http.Flusher
+			http.Hijacker
+			io.ReaderFrom
+		}{cw, cw, cw, cw}
+	case pusherBit | readerFromBit:
+		return c, struct {
+			http.ResponseWriter
+			http.Pusher
+			io.ReaderFrom
+		}{cw, cw, cw}
+	case flusherBit | pusherBit | readerFromBit:
+		return c, struct {
+			http.ResponseWriter
+			http.Flusher
+			http.Pusher
+			io.ReaderFrom
+		}{cw, cw, cw, cw}
+	case hijackerBit | pusherBit | readerFromBit:
+		return c, struct {
+			http.ResponseWriter
+			http.Hijacker
+			http.Pusher
+			io.ReaderFrom
+		}{cw, cw, cw, cw}
+	case flusherBit | hijackerBit | pusherBit | readerFromBit:
+		return c, struct {
+			http.ResponseWriter
+			http.Flusher
+			http.Hijacker
+			http.Pusher
+			io.ReaderFrom
+		}{cw, cw, cw, cw, cw}
+	}
+
+	return c, cw
+}
+
+// Size returns the number of bytes written to response body.
+func (c *ResponseCapture) Size() int64 {
+	return c.size
+}
+
+// Status returns the HTTP status sent to client. Returns zero whether HTTP
+// headers was not sent yet.
+func (c *ResponseCapture) Status() int {
+	return c.status
+}
+
+// WroteHeader returns whether HTTP headers was already sent.
+func (c *ResponseCapture) WroteHeader() bool {
+	return c.wroteHeader
+}
+
+func (c *ResponseCapture) writeHeader(status int) {
+	if c.wroteHeader {
+		return
+	}
+	c.status = status
+	c.wroteHeader = true
+}
+
+// A captureWriter represents a ResponseWriter which records response into a
+// ResponseCapture.
+type captureWriter struct {
+	w       http.ResponseWriter
+	capture *ResponseCapture
+}
+
+func (cw *captureWriter) Header() http.Header {
+	return cw.w.Header()
+}
+
+func (cw *captureWriter) Write(b []byte) (int, error) {
+	cw.capture.writeHeader(http.StatusOK)
+	n, err := cw.w.Write(b)
+	cw.capture.size += int64(n)
+	return n, err
+}
+
+func (cw *captureWriter) WriteHeader(status int) {
+	cw.capture.writeHeader(status)
+	cw.w.WriteHeader(status)
+}
+
+func (cw *captureWriter) Flush() {
+	cw.capture.writeHeader(http.StatusOK)
+	cw.w.(http.Flusher).Flush()
+}
+
+func (cw *captureWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	return cw.w.(http.Hijacker).Hijack()
+}
+
+func (cw *captureWriter) Push(target string, opts *http.PushOptions) error {
+	return cw.w.(http.Pusher).Push(target, opts)
+}
+
+func (cw *captureWriter) ReadFrom(src io.Reader) (int64, error) {
+	cw.capture.writeHeader(http.StatusOK)
+	n, err := cw.w.(io.ReaderFrom).ReadFrom(src)
+	cw.capture.size += n
+	return n, err
+}
@@ -0,0 +1,105 @@
+/*
+ * Copyright 2016 Fabrício Godoy
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package web
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCSPBuilder(t *testing.T) {
+	policy := NewCSP().
+		DefaultSrc(CSPSelf).
+		ScriptSrc(CSPSelf, "https://cdn.example.com").
+		ObjectSrc(CSPNone).
+		DefaultSrc(CSPNone).
+		UpgradeInsecureRequests().
+		Build()
+
+	expected := "default-src 'none'; " +
+		"script-src 'self' https://cdn.example.com; " +
+		"object-src 'none'; " +
+		"upgrade-insecure-requests"
+	if policy != expected {
+		t.Errorf("Unexpected policy: '%s' instead of '%s'", policy, expected)
+	}
+}
+
+func TestSecurityHeaders(t *testing.T) {
+	handler := NewChain(NewSecurityHeaders().SecurityHandler).ThenFunc(
+		func(w http.ResponseWriter, r *http.Request) {})
+
+	resp := httptest.NewRecorder()
+	handler.ServeHTTP(resp, httptest.NewRequest("GET", "/", nil))
+
+	expected := map[string]string{
+		"Strict-Transport-Security": "max-age=31536000; includeSubDomains",
+		"X-Content-Type-Options":    "nosniff",
+		"X-Frame-Options":           "DENY",
+		"Referrer-Policy":           "strict-origin-when-cross-origin",
+	}
+	for k, v := range expected {
+		if resp.Header().Get(k) != v {
+			t.Errorf("Unexpected '%s' header: '%s'", k, resp.Header().Get(k))
+		}
+	}
+	if !strings.Contains(
+		resp.Header().Get("Content-Security-Policy"), "default-src 'self'") {
+		t.Errorf("Unexpected policy: %s",
+			resp.Header().Get("Content-Security-Policy"))
+	}
+}
+
+func TestSecurityHeadersNonce(t *testing.T) {
+	s := SecurityHeaders{
+		HSTSMaxAge:  time.Hour,
+		HSTSPreload: true,
+		ContentSecurityPolicy: NewCSP().
+			ScriptSrc(CSPNonce, CSPStrictDynamic).
+			Build(),
+	}
+
+	var nonces []string
+	handler := NewChain(s.SecurityHandler).ThenFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			nonces = append(nonces, CSPNonceFrom(r))
+		})
+
+	for i := 0; i < 2; i++ {
+		resp := httptest.NewRecorder()
+		handler.ServeHTTP(resp, httptest.NewRequest("GET", "/", nil))
+
+		expected := "script-src 'nonce-" + nonces[i] + "' 'strict-dynamic'"
+		if v := resp.Header().Get("Content-Security-Policy"); v != expected {
+			t.Errorf("Unexpected policy: '%s' instead of '%s'", v, expected)
+		}
+		if v := resp.Header().Get("Strict-Transport-Security"); v !=
+			"max-age=3600; preload" {
+			t.Errorf("Unexpected HSTS: %s", v)
+		}
+		if len(resp.Header().Get("X-Frame-Options")) > 0 {
+			t.Error("Empty fields should not be sent")
+		}
+	}
+
+	if len(nonces[0]) == 0 || nonces[0] == nonces[1] {
+		t.Errorf("Each request should have an unique nonce: %v", nonces)
+	}
+}
@@ -0,0 +1,169 @@
+/*
+ * Copyright (C) 2016 Fabrício Godoy <skarllot@gmail.com>
+ *
+ * This program is free software; you can redistribute it and/or
+ * modify it under the terms of the GNU General Public License
+ * as published by the Free Software Foundation; either version 2
+ * of the License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU General Public License for more details.
+ *
+ * You should have received a copy of the GNU General Public License
+ * along with this program; if not, write to the Free Software
+ * Foundation, Inc., 59 Temple Place - Suite 330, Boston, MA  02111-1307, USA.
+ */
+
+package web
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+
+	"gopkg.in/vmihailenco/msgpack.v2"
+)
+
+// A SessionCodec defines rules for a type that serializes session data before
+// it is sent to store.
+type SessionCodec interface {
+	// Name returns an unique name that identifies current codec.
+	Name() string
+
+	// Encode serializes specified value.
+	Encode(v interface{}) ([]byte, error)
+
+	// Decode deserializes data to value pointed to by ref.
+	Decode(data []byte, ref interface{}) error
+}
+
+// A SessionMigrationFunc represents a function that decodes session data
+// stored by an older version to value pointed to by ref.
+type SessionMigrationFunc func(codec SessionCodec, data []byte, ref interface{}) error
+
+// A JSONSessionCodec represents a SessionCodec backed by JSON encoding.
+type JSONSessionCodec struct{}
+
+// Name returns an unique name that identifies current codec.
+func (JSONSessionCodec) Name() string {
+	return "json"
+}
+
+// Encode serializes specified value.
+func (JSONSessionCodec) Encode(v interface{}) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+// Decode deserializes data to value pointed to by ref.
+func (JSONSessionCodec) Decode(data []byte, ref interface{}) error {
+	return json.Unmarshal(data, ref)
+}
+
+// A GobSessionCodec represents a SessionCodec backed by gob encoding.
+//
+// Interface values must be registered using gob.Register.
+type GobSessionCodec struct{}
+
+// Name returns an unique name that identifies current codec.
+func (GobSessionCodec) Name() string {
+	return "gob"
+}
+
+// Encode serializes specified value.
+func (GobSessionCodec) Encode(v interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(v); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// Decode deserializes data to value pointed to by ref.
+func (GobSessionCodec) Decode(data []byte, ref interface{}) error {
+	return gob.NewDecoder(bytes.NewReader(data)).Decode(ref)
+}
+
+// A MsgpackSessionCodec represents a SessionCodec backed by MessagePack
+// encoding.
+type MsgpackSessionCodec struct{}
+
+// Name returns an unique name that identifies current codec.
+func (MsgpackSessionCodec) Name() string {
+	return "msgpack"
+}
+
+// Encode serializes specified value.
+func (MsgpackSessionCodec) Encode(v interface{}) ([]byte, error) {
+	return msgpack.Marshal(v)
+}
+
+// Decode deserializes data to value pointed to by ref.
+func (MsgpackSessionCodec) Decode(data []byte, ref interface{}) error {
+	return msgpack.Unmarshal(data, ref)
+}
+
+// A sessionEnvelope represents session data as sent to store.
+type sessionEnvelope struct {
+	// Version of stored data.
+	Version int `json:"v"`
+	// Name of codec used to encode data.
+	Codec string `json:"c"`
+	// Encoded data.
+	Data []byte `json:"d,omitempty"`
+}
+
+func encodeSession(
+	codec SessionCodec,
+	version int,
+	value interface{},
+) ([]byte, error) {
+	env := sessionEnvelope{
+		Version: version,
+		Codec:   codec.Name(),
+	}
+
+	if value != nil {
+		data, err := codec.Encode(value)
+		if err != nil {
+			return nil, err
+		}
+		env.Data = data
+	}
+
+	return json.Marshal(&env)
+}
+
+func decodeSession(
+	codec SessionCodec,
+	version int,
+	migrations map[int]SessionMigrationFunc,
+	raw []byte,
+	ref interface{},
+) (int, error) {
+	var env sessionEnvelope
+	if err := json.Unmarshal(raw, &env); err != nil {
+		return 0, err
+	}
+	if env.Codec != codec.Name() {
+		return env.Version, UnknownCodecError(env.Codec)
+	}
+	if ref == nil || len(env.Data) == 0 {
+		return env.Version, nil
+	}
+
+	if env.Version != version {
+		migrate, ok := migrations[env.Version]
+		if !ok {
+			return env.Version, UnsupportedVersionError(env.Version)
+		}
+		return env.Version, migrate(codec, env.Data, ref)
+	}
+
+	return env.Version, codec.Decode(env.Data, ref)
+}
+
+var _ SessionCodec = JSONSessionCodec{}
+var _ SessionCodec = GobSessionCodec{}
+var _ SessionCodec = MsgpackSessionCodec{}
@@ -27,8 +27,11 @@ import (
 // A SessionStore provides a temporary token to uniquely identify an user
 // session.
 type SessionStore struct {
-	cache  data.Store
-	salter *crypt.Salter
+	cache      data.Store
+	salter     *crypt.Salter
+	codec      SessionCodec
+	version    int
+	migrations map[int]SessionMigrationFunc
 }
 
 // Count gets the number of tokens stored by current instance.
@@ -43,13 +46,32 @@ func (s *SessionStore) Count() (int, error) {
 //
 // Errors:
 // InvalidTokenError when requested token could not be found.
+//
+// SessionDecodeError when stored value could not be decoded by current codec.
 func (s *SessionStore) Get(token string, ref interface{}) error {
-	err := s.cache.Get(token, ref)
+	if s.codec == nil {
+		err := s.cache.Get(token, ref)
+		if _, ok := err.(dot.InvalidKeyError); ok {
+			return InvalidTokenError(token)
+		}
+
+		return err
+	}
+
+	var raw []byte
+	err := s.cache.Get(token, &raw)
 	if _, ok := err.(dot.InvalidKeyError); ok {
 		return InvalidTokenError(token)
 	}
+	if err != nil {
+		return err
+	}
 
-	return err
+	version, err := decodeSession(s.codec, s.version, s.migrations, raw, ref)
+	if err != nil {
+		return &SessionDecodeError{token, version, err}
+	}
+	return nil
 }
 
 // Add creates a new unique token and stores it into current SessionCache
@@ -60,13 +82,20 @@ func (s *SessionStore) Get(token string, ref interface{}) error {
 // io.ErrUnexpectedEOF when random source cannot deliver enough bytes.
 //
 // dot.DuplicatedKeyError when generated key already exists.
+//
+// Any error returned by current codec when value could not be encoded.
 func (s *SessionStore) Add(value interface{}) (string, error) {
 	strSum, err := s.salter.Token(0)
 	if err != nil {
 		return "", err
 	}
 
-	err = s.cache.Add(strSum, value)
+	stored, err := s.encode(value)
+	if err != nil {
+		return "", err
+	}
+
+	err = s.cache.Add(strSum, stored)
 	if err != nil {
 		return "", err
 	}
@@ -90,8 +119,15 @@ func (s *SessionStore) Delete(token string) error {
 //
 // Errors:
 // InvalidTokenError when requested token could not be found.
+//
+// Any error returned by current codec when value could not be encoded.
 func (s *SessionStore) Set(token string, value interface{}) error {
-	err := s.cache.Set(token, value)
+	stored, err := s.encode(value)
+	if err != nil {
+		return err
+	}
+
+	err = s.cache.Set(token, stored)
 	if err != nil {
 		return InvalidTokenError(token)
 	}
@@ -103,3 +139,11 @@ func (s *SessionStore) Set(token string, value interface{}) error {
 func (s *SessionStore) SetTransient(val bool) {
 	s.cache.SetTransient(val)
 }
+
+// encode serializes specified value using current codec, when defined.
+func (s *SessionStore) encode(value interface{}) (interface{}, error) {
+	if s.codec == nil {
+		return value, nil
+	}
+	return encodeSession(s.codec, s.version, value)
+}
@@ -199,3 +199,134 @@ func BenchmarkSessionCreationFast(b *testing.B) {
 		ts.Add(nil)
 	}
 }
+
+type SessionFoo struct {
+	Name  string
+	Count int
+}
+
+func TestSessionCodec(t *testing.T) {
+	codecs := []SessionCodec{
+		JSONSessionCodec{},
+		GobSessionCodec{},
+		MsgpackSessionCodec{},
+	}
+
+	for _, codec := range codecs {
+		store := memstore.New(time.Millisecond*100, false)
+		ts := NewSessionStore().
+			SalterFast([]byte(TokenSalt)).
+			Store(store).
+			Codec(codec).
+			Build()
+
+		value := SessionFoo{"Lorem ipsum", 42}
+		token, err := ts.Add(value)
+		if err != nil {
+			t.Fatalf("The session could not be generated by %s codec: %v",
+				codec.Name(), err)
+		}
+
+		var raw []byte
+		if err := store.Get(token, &raw); err != nil {
+			t.Errorf("The %s codec should store encoded data: %v",
+				codec.Name(), err)
+		}
+
+		var decoded SessionFoo
+		if err := ts.Get(token, &decoded); err != nil {
+			t.Errorf("The session could not be read by %s codec: %v",
+				codec.Name(), err)
+		}
+		if decoded != value {
+			t.Errorf("The %s codec decoded %#v instead of %#v",
+				codec.Name(), decoded, value)
+		}
+
+		empty, err := ts.Add(nil)
+		if err != nil {
+			t.Errorf("The empty session could not be generated by %s codec: %v",
+				codec.Name(), err)
+		}
+		if err := ts.Get(empty, &decoded); err != nil {
+			t.Errorf("The empty session could not be read by %s codec: %v",
+				codec.Name(), err)
+		}
+	}
+}
+
+func TestSessionCodecVersion(t *testing.T) {
+	store := memstore.New(time.Millisecond*100, false)
+	oldStore := NewSessionStore().
+		SalterFast([]byte(TokenSalt)).
+		Store(store).
+		Codec(JSONSessionCodec{}).
+		Build()
+	token, err := oldStore.Add(map[string]string{"Title": "Lorem ipsum"})
+	if err != nil {
+		t.Fatalf("The session could not be generated: %v", err)
+	}
+
+	var value SessionFoo
+	ts := NewSessionStore().
+		SalterFast([]byte(TokenSalt)).
+		Store(store).
+		Codec(JSONSessionCodec{}).
+		Version(1).
+		Build()
+	err = ts.Get(token, &value)
+	if _, ok := err.(*SessionDecodeError); !ok {
+		t.Errorf("Expected SessionDecodeError but got %#v", err)
+	}
+
+	ts = NewSessionStore().
+		SalterFast([]byte(TokenSalt)).
+		Store(store).
+		Codec(JSONSessionCodec{}).
+		Version(1).
+		Migration(0, func(c SessionCodec, data []byte, ref interface{}) error {
+			var old map[string]string
+			if err := c.Decode(data, &old); err != nil {
+				return err
+			}
+			ref.(*SessionFoo).Name = old["Title"]
+			return nil
+		}).
+		Build()
+	if err := ts.Get(token, &value); err != nil {
+		t.Fatalf("The session could not be migrated: %v", err)
+	}
+	if value.Name != "Lorem ipsum" {
+		t.Errorf("The session was migrated incorrectly: %#v", value)
+	}
+}
+
+func TestSessionCodecFailure(t *testing.T) {
+	store := memstore.New(time.Millisecond*100, false)
+	ts := NewSessionStore().
+		SalterFast([]byte(TokenSalt)).
+		Store(store).
+		Codec(GobSessionCodec{}).
+		Build()
+
+	token, err := ts.Add(SessionFoo{"Lorem ipsum", 42})
+	if err != nil {
+		t.Fatalf("The session could not be generated: %v", err)
+	}
+	if err := store.Set(token, []byte("{}")); err != nil {
+		t.Fatalf("The session could not be changed: %v", err)
+	}
+
+	var value SessionFoo
+	err = ts.Get(token, &value)
+	decErr, ok := err.(*SessionDecodeError)
+	if !ok {
+		t.Fatalf("Expected SessionDecodeError but got %#v", err)
+	}
+	if decErr.Token != token {
+		t.Errorf("Unexpected token on error: %s", decErr.Token)
+	}
+	if _, ok := decErr.Err.(UnknownCodecError); !ok {
+		t.Errorf("Expected UnknownCodecError but got %#v", decErr.Err)
+	}
+}
@@ -30,6 +30,14 @@ type SessionStoreBuilder interface {
 	// Build creates and returns a new SessionStore.
 	Build() *SessionStore
 
+	// Codec sets a codec to serialize session data before it is sent to store.
+	// It is required by stores which keep data out of process.
+	Codec(SessionCodec) SessionStoreBuilder
+
+	// Migration sets a function to decode session data stored by specified
+	// older version.
+	Migration(int, SessionMigrationFunc) SessionStoreBuilder
+
 	// Salter sets a custom salter to generate random tokens.
 	Salter(*crypt.Salter) SessionStoreBuilder
 
@@ -47,11 +55,18 @@ type SessionStoreBuilder interface {
 
 	// Store sets a custom Store to store sessions.
 	Store(data.Store) SessionStoreBuilder
+
+	// Version sets current version of session data, which is stored alongside
+	// encoded data. Defaults to zero.
+	Version(int) SessionStoreBuilder
 }
 
 type ssb struct {
-	store  data.Store
-	salter *crypt.Salter
+	store      data.Store
+	salter     *crypt.Salter
+	codec      SessionCodec
+	version    int
+	migrations map[int]SessionMigrationFunc
 }
 
 // NewSessionStore creates a new builder for SessionStore.
@@ -60,12 +75,36 @@ func NewSessionStore() SessionStoreBuilder {
 }
 
 func (b *ssb) Build() *SessionStore {
+	migrations := make(map[int]SessionMigrationFunc, len(b.migrations))
+	for k, v := range b.migrations {
+		migrations[k] = v
+	}
+
 	return &SessionStore{
-		salter: b.salter,
-		cache:  b.store,
+		salter:     b.salter,
+		cache:      b.store,
+		codec:      b.codec,
+		version:    b.version,
+		migrations: migrations,
 	}
 }
 
+func (b *ssb) Codec(codec SessionCodec) SessionStoreBuilder {
+	b.codec = codec
+	return b
+}
+
+func (b *ssb) Migration(
+	version int,
+	fn SessionMigrationFunc,
+) SessionStoreBuilder {
+	if b.migrations == nil {
+		b.migrations = make(map[int]SessionMigrationFunc)
+	}
+	b.migrations[version] = fn
+	return b
+}
+
 func (b *ssb) Salter(salter *crypt.Salter) SessionStoreBuilder {
 	b.salter = salter
 	return b
@@ -86,3 +125,8 @@ func (b *ssb) Store(store data.Store) SessionStoreBuilder {
 	b.store = store
 	return b
 }
+
+func (b *ssb) Version(version int) SessionStoreBuilder {
+	b.version = version
+	return b
+}
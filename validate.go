@@ -0,0 +1,272 @@
+/*
+ * Copyright 2016 Fabrício Godoy
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package web
+
+import (
+	"fmt"
+	"net/mail"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const validateTagName = "validate"
+
+var validateRules = map[string]bool{
+	"required": true,
+	"min":      true,
+	"max":      true,
+	"len":      true,
+	"email":    true,
+	"oneof":    true,
+}
+
+// A Validator defines rules for a type that validates its own fields.
+type Validator interface {
+	// Validate returns the errors of invalid fields, or nil when every field
+	// is valid.
+	Validate() []FieldError
+}
+
+// Validate validates specified object using the rules defined by its
+// `validate` struct tags and, when implemented, by Validator interface.
+//
+// The supported rules are: required, which rejects zero values; min, max and
+// len, which compare numbers by value and strings, slices and maps by length;
+// email; and oneof, which takes space-separated allowed values. Rules are
+// separated by commas, as in `validate:"required,min=1"`, and rules other than
+// required are not checked for zero values. Nested structs, and slices, arrays
+// and map values of structs are validated too. Fields of embedded structs are
+// reported as fields of embedding struct, as JSON encoding flattens them.
+// Unknown rules, malformed parameters and size rules on types without size are
+// ignored.
+func Validate(obj interface{}) []FieldError {
+	var result []FieldError
+	validateValue(reflect.ValueOf(obj), "", &result)
+
+	if v, ok := obj.(Validator); ok {
+		result = append(result, v.Validate()...)
+	}
+	return result
+}
+
+// validationError creates a JSONError listing specified field errors.
+func validationError(fields []FieldError) *JSONError {
+	jerr := NewJSONError().
+		CustomError("", "ValidationError", "The request has invalid fields").
+		Status(StatusUnprocessableEntity).
+		Build()
+	jerr.Fields = fields
+	return jerr
+}
+
+func validateValue(v reflect.Value, pointer string, result *[]FieldError) {
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return
+		}
+		v = v.Elem()
+	}
+
+	switch v.Kind() {
+	case reflect.Struct:
+		t := v.Type()
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			if isPromotedStruct(field) {
+				// Fields of embedded struct are flattened by JSON encoding
+				validateValue(v.Field(i), pointer, result)
+				continue
+			}
+			if len(field.PkgPath) > 0 {
+				continue
+			}
+			name, skip := jsonFieldName(field)
+			if skip {
+				continue
+			}
+
+			fieldPointer := pointer + JSONPointer(name)
+			fv := v.Field(i)
+			if tag := field.Tag.Get(validateTagName); len(tag) > 0 {
+				validateField(fv, fieldPointer, tag, result)
+			}
+			validateValue(fv, fieldPointer, result)
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			validateValue(v.Index(i), pointer+JSONPointer(strconv.Itoa(i)),
+				result)
+		}
+	case reflect.Map:
+		keys := make([]string, 0, v.Len())
+		values := make(map[string]reflect.Value, v.Len())
+		for _, k := range v.MapKeys() {
+			key := fmt.Sprint(k.Interface())
+			keys = append(keys, key)
+			values[key] = v.MapIndex(k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			validateValue(values[k], pointer+JSONPointer(k), result)
+		}
+	}
+}
+
+// isPromotedStruct returns whether specified field is an embedded struct
+// whose fields are promoted by JSON encoding.
+func isPromotedStruct(field reflect.StructField) bool {
+	if !field.Anonymous || len(field.Tag.Get("json")) > 0 {
+		return false
+	}
+
+	t := field.Type
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t.Kind() == reflect.Struct
+}
+
+func validateField(
+	v reflect.Value,
+	pointer, tag string,
+	result *[]FieldError,
+) {
+	zero := isZeroValue(v)
+	for _, rule := range strings.Split(tag, ",") {
+		name, param := rule, ""
+		if i := strings.IndexByte(rule, '='); i >= 0 {
+			name, param = rule[:i], rule[i+1:]
+		}
+		if !validateRules[name] {
+			continue
+		}
+		if zero && name != "required" {
+			continue
+		}
+
+		msg := checkRule(v, name, param)
+		if len(msg) == 0 {
+			continue
+		}
+
+		var value interface{}
+		if v.CanInterface() {
+			value = v.Interface()
+		}
+		*result = append(*result, FieldError{pointer, name, msg, value})
+		return
+	}
+}
+
+// checkRule returns a message when specified value violates the rule.
+func checkRule(v reflect.Value, name, param string) string {
+	switch name {
+	case "required":
+		if isZeroValue(v) {
+			return "The field is required"
+		}
+	case "min", "max", "len":
+		for v.Kind() == reflect.Ptr {
+			v = v.Elem()
+		}
+		limit, err := strconv.ParseFloat(param, 64)
+		if err != nil {
+			return ""
+		}
+		size, unit, ok := ruleSize(v)
+		switch {
+		case !ok:
+			return ""
+		case name == "min" && size < limit:
+			return fmt.Sprintf("The field must be at least %s%s", param, unit)
+		case name == "max" && size > limit:
+			return fmt.Sprintf("The field must be at most %s%s", param, unit)
+		case name == "len" && size != limit:
+			return fmt.Sprintf("The field must have exactly %s%s", param, unit)
+		}
+	case "email":
+		s := fmt.Sprint(reflect.Indirect(v))
+		addr, err := mail.ParseAddress(s)
+		if err != nil || addr.Address != s {
+			return "The field must be a valid e-mail address"
+		}
+	case "oneof":
+		s := fmt.Sprint(reflect.Indirect(v))
+		for _, allowed := range strings.Fields(param) {
+			if s == allowed {
+				return ""
+			}
+		}
+		return fmt.Sprintf("The field must be one of: %s",
+			strings.Join(strings.Fields(param), ", "))
+	}
+
+	return ""
+}
+
+// ruleSize returns the number or length which is compared by size rules, and
+// whether size rules are supported by value type.
+func ruleSize(v reflect.Value) (float64, string, bool) {
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32,
+		reflect.Int64:
+		return float64(v.Int()), "", true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32,
+		reflect.Uint64:
+		return float64(v.Uint()), "", true
+	case reflect.Float32, reflect.Float64:
+		return v.Float(), "", true
+	case reflect.String:
+		return float64(len([]rune(v.String()))), " characters", true
+	case reflect.Slice, reflect.Array, reflect.Map:
+		return float64(v.Len()), " items", true
+	}
+
+	return 0, "", false
+}
+
+func isZeroValue(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface, reflect.Slice, reflect.Map:
+		if v.IsNil() {
+			return true
+		}
+		if v.Kind() == reflect.Slice || v.Kind() == reflect.Map {
+			return v.Len() == 0
+		}
+		return false
+	}
+	return v.IsZero()
+}
+
+// jsonFieldName returns the name of specified field as encoded by JSON, and
+// whether field is not encoded.
+func jsonFieldName(field reflect.StructField) (string, bool) {
+	tag := field.Tag.Get("json")
+	if tag == "-" {
+		return "", true
+	}
+	if i := strings.IndexByte(tag, ','); i >= 0 {
+		tag = tag[:i]
+	}
+	if len(tag) == 0 {
+		return field.Name, false
+	}
+	return tag, false
+}
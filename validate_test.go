@@ -0,0 +1,176 @@
+/*
+ * Copyright 2016 Fabrício Godoy
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package web
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+type ValidFoo struct {
+	Name  string      `json:"name" validate:"required,max=10"`
+	Email string      `json:"email" validate:"email"`
+	Age   int         `json:"age" validate:"min=18,max=130"`
+	Kind  string      `json:"kind,omitempty" validate:"oneof=admin user"`
+	Tags  []string    `json:"tags" validate:"len=2"`
+	Items []ValidItem `json:"items"`
+}
+
+type ValidItem struct {
+	Price *float64 `json:"price" validate:"required,min=0.5"`
+}
+
+func (f *ValidFoo) Validate() []FieldError {
+	if f.Name == "root" && f.Kind != "admin" {
+		return []FieldError{{
+			Pointer: "/kind",
+			Rule:    "root",
+			Message: "The root user must be an admin",
+		}}
+	}
+	return nil
+}
+
+func TestValidate(t *testing.T) {
+	price := 0.25
+	foo := &ValidFoo{
+		Name:  "Lorem ipsum dolor",
+		Email: "foo@",
+		Age:   10,
+		Kind:  "guest",
+		Tags:  []string{"a"},
+		Items: []ValidItem{{&price}, {nil}},
+	}
+
+	fields := Validate(foo)
+	expected := []struct {
+		pointer string
+		rule    string
+	}{
+		{"/name", "max"},
+		{"/email", "email"},
+		{"/age", "min"},
+		{"/kind", "oneof"},
+		{"/tags", "len"},
+		{"/items/0/price", "min"},
+		{"/items/1/price", "required"},
+	}
+	if len(fields) != len(expected) {
+		t.Fatalf("Unexpected field errors: %#v", fields)
+	}
+	for i, v := range expected {
+		if fields[i].Pointer != v.pointer || fields[i].Rule != v.rule {
+			t.Errorf("Unexpected field error #%d: %#v", i, fields[i])
+		}
+	}
+	if fields[2].Value != 10 {
+		t.Errorf("Rejected value should be kept: %#v", fields[2])
+	}
+
+	price = 1
+	valid := &ValidFoo{
+		Name:  "root",
+		Email: "foo@example.com",
+		Age:   30,
+		Tags:  []string{"a", "b"},
+		Items: []ValidItem{{&price}},
+	}
+	fields = Validate(valid)
+	if len(fields) != 1 || fields[0].Rule != "root" {
+		t.Errorf("Validator interface should be called: %#v", fields)
+	}
+}
+
+func TestJSONReadValidation(t *testing.T) {
+	body := `{"name":"","email":"foo@example.com","age":30,"tags":["a","b"]}`
+	resp := httptest.NewRecorder()
+
+	var foo ValidFoo
+	if !JSONRead(nopCloser{strings.NewReader(body)}, BodyMaxLength, &foo, resp) {
+		t.Fatal("JSONRead should not validate object")
+	}
+
+	resp = httptest.NewRecorder()
+	reader := JSONReader{MaxLength: BodyMaxLength, Validate: true}
+	if reader.Read(nopCloser{strings.NewReader(body)}, &foo, resp) {
+		t.Fatal("Invalid object should not be accepted")
+	}
+	if resp.Code != StatusUnprocessableEntity {
+		t.Errorf("Unexpected status: %d", resp.Code)
+	}
+
+	var jerr JSONError
+	if err := json.NewDecoder(resp.Body).Decode(&jerr); err != nil {
+		t.Fatalf("Error decoding JSONError: %v", err)
+	}
+	if len(jerr.Fields) != 1 || jerr.Fields[0].Pointer != "/name" {
+		t.Errorf("Unexpected field errors: %#v", jerr.Fields)
+	}
+}
+
+func TestValidateUnsupportedRules(t *testing.T) {
+	obj := &struct {
+		Name  string    `validate:"foo"`
+		Age   int       `validate:"gt=0,min=x"`
+		Since time.Time `validate:"min=1"`
+	}{Name: "a", Age: 3, Since: time.Now()}
+
+	if fields := Validate(obj); len(fields) != 0 {
+		t.Errorf("Unsupported rules should be ignored: %#v", fields)
+	}
+}
+
+type nopCloser struct {
+	*strings.Reader
+}
+
+func (nopCloser) Close() error {
+	return nil
+}
+
+type ValidBase struct {
+	Name string `json:"name" validate:"required"`
+}
+
+type ValidEmbedding struct {
+	ValidBase
+	Tagged ValidBase            `json:"tagged"`
+	Items  map[string]ValidItem `json:"items"`
+}
+
+func TestValidateEmbeddedAndMap(t *testing.T) {
+	price := 1.0
+	obj := &ValidEmbedding{
+		Items: map[string]ValidItem{
+			"b": {nil},
+			"a": {&price},
+		},
+	}
+
+	fields := Validate(obj)
+	pointers := make([]string, 0, len(fields))
+	for _, f := range fields {
+		pointers = append(pointers, f.Pointer)
+	}
+	expected := []string{"/name", "/tagged/name", "/items/b/price"}
+	if !equalStrings(pointers, expected) {
+		t.Errorf("Unexpected field pointers: %v", pointers)
+	}
+}